@@ -53,7 +53,10 @@ func AllCommands(builder cli.Builder) []*cobra.Command {
 		commands.NewConfigCmd(builder).Cmd(),
 		NewMenuCmd(builder).Cmd(),
 		NewOrderCmd(builder).Cmd(),
+		NewStoreCmd(builder).Cmd(),
+		NewPriceCmd(builder).Cmd(),
 		commands.NewAddAddressCmd(builder, os.Stdin).Cmd(),
+		commands.NewCacheCmd(builder, os.Stdin).Cmd(),
 		commands.NewCompletionCmd(builder),
 	}
 }
@@ -76,7 +79,7 @@ func senderr(e error, msg string, code int) *ErrMsg {
 // Execute runs the root command
 func Execute(args []string, dir string) (msg *ErrMsg) {
 	app := NewApp(os.Stdout)
-	err := app.Init(dir)
+	err := app.Init(dir, args)
 	if err != nil {
 		return senderr(err, "Internal Error", 1)
 	}
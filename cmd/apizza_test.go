@@ -12,6 +12,7 @@ import (
 
 	"github.com/harrybrwn/apizza/cmd/cli"
 	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/opts"
 	"github.com/harrybrwn/apizza/pkg/config"
 	"github.com/harrybrwn/apizza/pkg/errs"
 	"github.com/harrybrwn/apizza/pkg/tests"
@@ -86,7 +87,75 @@ func TestAppResetFlag(t *testing.T) {
 	r.ClearBuf()
 }
 
+func TestDBPath(t *testing.T) {
+	for _, tc := range []struct {
+		args          []string
+		conf, db, out string
+	}{
+		{[]string{"--db", "/tmp/one.db", "menu"}, "", "/tmp/one.db", ""},
+		{[]string{"cart", "-A", "home", "--db=/tmp/two.db"}, "", "/tmp/two.db", ""},
+		{[]string{"--store", "4336", "order", "--cvv=000"}, "", "", ""},
+		{[]string{"-vv", "--help"}, "", "", ""},
+		{[]string{"--config", "/tmp/joe.json", "config", "set", "name=joe"}, "/tmp/joe.json", "", ""},
+		{[]string{"--config=/tmp/a.json", "--db", "/tmp/a.db"}, "/tmp/a.json", "/tmp/a.db", ""},
+		{[]string{"menu", "--output", "menu.txt", "--json"}, "", "", "menu.txt"},
+	} {
+		var o opts.CliFlags
+		earlyFlags(tc.args, &o)
+		if o.ConfigFile != tc.conf || o.DBPath != tc.db || o.OutputFile != tc.out {
+			t.Errorf("earlyFlags(%q): got %q, %q, and %q, want %q, %q, and %q", tc.args,
+				o.ConfigFile, o.DBPath, o.OutputFile, tc.conf, tc.db, tc.out)
+		}
+	}
+
+	a := NewApp(ioutil.Discard)
+	if a.dbPath() == "" {
+		t.Error("should have a default database path")
+	}
+	a.conf.DBPath = "/tmp/conf.db"
+	if a.dbPath() != "/tmp/conf.db" {
+		t.Error("should use the database path from the config")
+	}
+	a.gOpts.DBPath = "/tmp/flag.db"
+	if a.dbPath() != "/tmp/flag.db" {
+		t.Error("the --db flag should override the config")
+	}
+}
+
+func TestOutputFile(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	a := CreateApp(r.ToApp())
+	tests.Check(a.setOutputFile())
+	if a.Output() != r.Output() {
+		t.Error("should not change the output without --output")
+	}
+
+	dir, err := ioutil.TempDir("", "apizza")
+	tests.Fatal(err)
+	defer os.RemoveAll(dir)
+	file := fp.Join(dir, "out.txt")
+	tests.Check(ioutil.WriteFile(file, []byte("old output that should be truncated"), 0644))
+
+	a.gOpts.OutputFile = file
+	tests.Check(a.setOutputFile())
+	a.Printf("menu dump\n")
+	tests.Check(a.Cleanup())
+	raw, err := ioutil.ReadFile(file)
+	tests.Check(err)
+	tests.Compare(t, string(raw), "menu dump\n")
+	if r.Out.Len() != 0 {
+		t.Error("nothing should be written to the builder's output")
+	}
+
+	a.gOpts.OutputFile = fp.Join(dir, "missing", "out.txt")
+	tests.Exp(a.setOutputFile(), "should not create an output file in a directory that does not exist")
+}
+
 func TestAppStoreFinder(t *testing.T) {
+	srv := cmdtest.NewServer()
+	defer srv.Close()
 	r := cmdtest.NewRecorder()
 	defer r.CleanUp()
 	a := CreateApp(r.ToApp())
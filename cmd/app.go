@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
+	"math"
 	"os"
 	"os/exec"
 	fp "path/filepath"
@@ -19,6 +23,7 @@ import (
 	"github.com/harrybrwn/apizza/pkg/config"
 	"github.com/harrybrwn/apizza/pkg/errs"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // App is the main app, the root command, and the Builder.
@@ -30,6 +35,13 @@ type App struct {
 	conf *cli.Config
 	addr *obj.Address
 	logf *os.File
+	outf *os.File
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	logger   *cli.Logger
+	progress *cli.Progress
+	geocoder dawg.Geocoder
 
 	// global apizza options
 	gOpts opts.CliFlags
@@ -47,7 +59,7 @@ func NewApp(out io.Writer) *App {
 		opts:  opts.ApizzaFlags{},
 	}
 	app.CliCommand = cli.NewCommand("apizza", "Dominos pizza from the command line.", app.Run)
-	app.StoreFinder = client.NewStoreGetterFunc(app.getService, app.Address)
+	app.StoreFinder = client.NewStoreGetterFunc(app.Context, app.getStoreID, app.getService, app.Address, app.Geocoder, app.DB)
 	app.SetOutput(out)
 	return app
 }
@@ -60,26 +72,75 @@ func CreateApp(db *cache.DataBase, conf *cli.Config, out io.Writer) *App {
 	return app
 }
 
-// Init wil setup the app.
-func (a *App) Init(dir string) error {
+// Init wil setup the app. The args are only used to find the
+// --config and --db flags because the config and database are
+// opened before any of the commands are built.
+func (a *App) Init(dir string, args []string) error {
 	if a.conf == nil {
 		a.conf = &cli.Config{}
 	}
 	a.initflags()
+	earlyFlags(args, &a.gOpts)
+	if err := a.setOutputFile(); err != nil {
+		return err
+	}
 	return errs.Pair(a.SetConfig(dir), a.InitDB())
 }
 
-// SetConfig for the the app
+// SetConfig for the the app. The file given to the --config
+// flag is used instead of the default file in dir.
 func (a *App) SetConfig(dir string) error {
+	if a.gOpts.ConfigFile != "" {
+		return config.SetConfigFile(dir, a.gOpts.ConfigFile, a.conf)
+	}
 	return config.SetConfig(dir, a.conf)
 }
 
-// InitDB for the app.
+// InitDB for the app. The database is opened at the path given by
+// the --db flag, then the db-path config field, then the default path.
 func (a *App) InitDB() (err error) {
-	a.db, err = data.OpenDatabase()
+	a.db, err = cache.GetDB(a.dbPath())
 	return
 }
 
+func (a *App) dbPath() string {
+	if a.gOpts.DBPath != "" {
+		return a.gOpts.DBPath
+	}
+	if a.conf != nil && a.conf.DBPath != "" {
+		return a.conf.DBPath
+	}
+	return data.DefaultDBPath()
+}
+
+// earlyFlags finds the values of the --config, --db, and --output
+// flags in a list of arguments and ignores all other flags.
+func earlyFlags(args []string, o *opts.CliFlags) {
+	flags := pflag.NewFlagSet("apizza", pflag.ContinueOnError)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.SetOutput(ioutil.Discard)
+	flags.Usage = func() {}
+	flags.StringVar(&o.ConfigFile, "config", "", "")
+	flags.StringVar(&o.DBPath, "db", "", "")
+	flags.StringVar(&o.OutputFile, "output", "", "")
+	flags.Parse(args)
+}
+
+// setOutputFile creates the file given by the --output flag and
+// uses it as the app's output. The file is closed by Cleanup.
+func (a *App) setOutputFile() error {
+	if a.gOpts.OutputFile == "" {
+		return nil
+	}
+	f, err := os.Create(a.gOpts.OutputFile)
+	if err != nil {
+		return fmt.Errorf("could not create output file: %v", err)
+	}
+	a.outf = f
+	a.SetOutput(f)
+	return nil
+}
+
 // DB returns the database
 func (a *App) DB() *cache.DataBase {
 	return a.db
@@ -101,7 +162,7 @@ func (a *App) Address() dawg.Address {
 		return a.addr
 	}
 	if a.conf.DefaultAddressName != "" {
-		addr, err := a.getDBAddress(a.conf.DefaultAddressName)
+		addr, err := a.getNamedAddress(a.conf.DefaultAddressName)
 		if err != nil {
 			fmt.Fprintf(os.Stderr,
 				"Warning: could not find an address named '%s'\n",
@@ -122,9 +183,71 @@ func (a *App) GlobalOptions() *opts.CliFlags {
 	return &a.gOpts
 }
 
+// Context returns the context used for requests to dominos. If the
+// --timeout flag was given, the context will time out after that duration.
+// The context also carries the retry settings from --retries and --retry-wait.
+func (a *App) Context() context.Context {
+	if a.ctx == nil {
+		return context.Background()
+	}
+	return a.ctx
+}
+
+// Logger returns the app's logger.
+func (a *App) Logger() *cli.Logger {
+	if a.logger == nil {
+		a.logger = cli.NewLogger(os.Stderr, a.gOpts.Verbose)
+	}
+	return a.logger
+}
+
+// Progress returns the app's progress spinner. It is only shown when stderr
+// is a terminal and it is disabled by --quiet and --verbose.
+func (a *App) Progress() *cli.Progress {
+	if a.progress == nil {
+		enabled := !a.gOpts.Quiet && a.gOpts.Verbose == 0 && cli.IsTerminal(os.Stderr)
+		a.progress = cli.NewProgress(os.Stderr, enabled)
+	}
+	return a.progress
+}
+
+// Geocoder returns the geocoder used to find stores near the address.
+func (a *App) Geocoder() dawg.Geocoder {
+	if a.geocoder == nil {
+		return dawg.DominosGeocoder{}
+	}
+	return a.geocoder
+}
+
+// SetGeocoder sets the geocoder used to find stores. Setting it to nil
+// will use the default.
+func (a *App) SetGeocoder(g dawg.Geocoder) {
+	a.geocoder = g
+}
+
+// Color returns true if the output should be colored, it depends on the
+// --color flag and whether the output is a terminal.
+func (a *App) Color() bool {
+	enabled, err := cli.ColorEnabled(a.gOpts.Color, a.Output())
+	return err == nil && enabled
+}
+
 // Cleanup cleans everything up.
 func (a *App) Cleanup() (err error) {
-	return errs.Pair(a.db.Close(), config.Save())
+	if a.cancel != nil {
+		a.cancel()
+	}
+	a.Progress().Stop()
+	err = errs.Pair(a.db.Close(), config.Save())
+	if a.outf != nil {
+		err = errs.Pair(err, a.outf.Close())
+		a.outf = nil
+	}
+	return err
+}
+
+func (a *App) getStoreID() string {
+	return a.gOpts.StoreID
 }
 
 func (a *App) getService() string {
@@ -192,13 +315,15 @@ func (a *App) initflags() {
 
 func (a *App) prerun(*cobra.Command, []string) (err error) {
 	if a.gOpts.ResetMenu {
-		err = a.DB().Delete("menu")
+		err = data.DeleteMenus(a.DB())
 	}
 	var e error
 	if a.gOpts.Address != "" {
-		// First look in the database as if the flag was a named address.
-		// Else check if the flag is a parsable address.
-		if a.db.WithBucket("addresses").Exists(a.gOpts.Address) {
+		// First look in the config and the database as if the flag was a
+		// named address. Else check if the flag is a parsable address.
+		if addr, ok := a.conf.NamedAddress(a.gOpts.Address); ok {
+			a.addr = addr
+		} else if a.db.WithBucket("addresses").Exists(a.gOpts.Address) {
 			newaddr, err := a.getDBAddress(a.gOpts.Address)
 			if err != nil {
 				return err
@@ -214,11 +339,34 @@ func (a *App) prerun(*cobra.Command, []string) (err error) {
 	}
 
 	if a.gOpts.Service != "" {
-		if !(a.gOpts.Service == dawg.Delivery || a.gOpts.Service == dawg.Carryout) {
-			return dawg.ErrBadService
+		// the config is not changed here so that the service
+		// flag does not get saved in the config file
+		service, err := cli.ParseService(a.gOpts.Service)
+		if err != nil {
+			return err
 		}
-		// BUG: setting the config field will implicitly change the config file
-		a.conf.Service = a.gOpts.Service
+		a.gOpts.Service = service
+	}
+
+	if a.gOpts.Retries < 0 {
+		return errors.New("--retries cannot be negative")
+	}
+	if a.gOpts.Rate < 0 || math.IsNaN(a.gOpts.Rate) {
+		return errors.New("--rate cannot be negative")
+	}
+	if _, e = cli.ColorEnabled(a.gOpts.Color, a.Output()); e != nil {
+		return e
+	}
+	a.ctx = dawg.WithRetry(context.Background(), a.gOpts.Retries, a.gOpts.RetryWait)
+	if a.gOpts.Rate > 0 {
+		a.ctx = dawg.WithRateLimit(a.ctx, dawg.NewRateLimiter(a.gOpts.Rate))
+	}
+	if a.gOpts.Verbose > 0 {
+		a.ctx = dawg.WithLogger(a.ctx, a.Logger())
+	}
+	a.ctx = cli.WithProgress(a.ctx, a.Progress())
+	if a.gOpts.Timeout > 0 {
+		a.ctx, a.cancel = context.WithTimeout(a.ctx, a.gOpts.Timeout)
 	}
 
 	if a.gOpts.LogFile != "" {
@@ -235,6 +383,14 @@ func (a *App) prerun(*cobra.Command, []string) (err error) {
 	return errs.Pair(err, e)
 }
 
+// getNamedAddress looks for an address in the config and then in the database.
+func (a *App) getNamedAddress(name string) (*obj.Address, error) {
+	if addr, ok := a.conf.NamedAddress(name); ok {
+		return addr, nil
+	}
+	return a.getDBAddress(name)
+}
+
 func (a *App) getDBAddress(key string) (*obj.Address, error) {
 	raw, err := a.db.WithBucket("addresses").Get(key)
 	if err != nil {
@@ -16,10 +16,16 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"math"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -30,24 +36,34 @@ import (
 	"github.com/harrybrwn/apizza/cmd/internal/data"
 	"github.com/harrybrwn/apizza/cmd/internal/obj"
 	"github.com/harrybrwn/apizza/cmd/internal/out"
+	"github.com/harrybrwn/apizza/cmd/opts"
 	"github.com/harrybrwn/apizza/dawg"
 	"github.com/harrybrwn/apizza/pkg/cache"
 	"github.com/harrybrwn/apizza/pkg/config"
+	"github.com/harrybrwn/apizza/pkg/errs"
 )
 
 // `apizza cart`
 type cartCmd struct {
 	cli.CliCommand
-	cart *cart.Cart
+	cart  *cart.Cart
+	gopts *opts.CliFlags
+	color func() bool
 
 	validate bool
 	price    bool
 	delete   bool
-	verbose  bool
 
 	add     []string
 	remove  string // yes, you can only remove one thing at a time
 	product string
+	coupons []string
+
+	exportFile string
+	importFile string
+	quantity   int
+	option     string
+	format     string
 
 	topping bool // not actually a flag anymore
 }
@@ -56,9 +72,14 @@ type cartCmd struct {
 
 func (c *cartCmd) Run(cmd *cobra.Command, args []string) (err error) {
 	out.SetOutput(cmd.OutOrStdout())
+	out.SetColor(c.color())
+	defer out.ResetOutput()
 	c.cart.SetOutput(c.Output())
+	if c.importFile != "" {
+		return c.importCart(args)
+	}
 	if len(args) < 1 {
-		return c.cart.PrintOrders(c.verbose)
+		return c.cart.PrintOrders(c.gopts.Verbose > 0)
 	}
 
 	if c.topping && c.product == "" {
@@ -80,12 +101,23 @@ func (c *cartCmd) Run(cmd *cobra.Command, args []string) (err error) {
 	if err = c.cart.SetCurrentOrder(args[0]); err != nil {
 		return err
 	}
+	if c.gopts.Service != "" {
+		c.cart.CurrentOrder.ServiceMethod = c.gopts.Service
+	}
 
 	if c.validate {
 		// validate the current order and stop
 		return c.cart.Validate()
 	}
 
+	if c.exportFile != "" {
+		f, err := os.Create(c.exportFile)
+		if err != nil {
+			return err
+		}
+		return errs.Pair(c.cart.Export(f), f.Close())
+	}
+
 	if len(c.remove) > 0 {
 		if c.topping {
 			for _, p := range c.cart.CurrentOrder.Products {
@@ -102,11 +134,20 @@ func (c *cartCmd) Run(cmd *cobra.Command, args []string) (err error) {
 		return c.cart.SaveAndReset()
 	}
 
+	if len(c.coupons) > 0 {
+		// the accepted coupons are saved even if some were rejected
+		err = c.cart.AddCoupons(c.coupons)
+		return errs.Pair(err, c.cart.SaveAndReset())
+	}
+
+	if c.option != "" && (len(c.add) == 0 || c.topping) {
+		return errors.New("--option can only be used when adding products with --add")
+	}
 	if len(c.add) > 0 {
 		if c.topping {
 			err = c.cart.AddToppings(c.product, c.add)
 		} else {
-			err = c.cart.AddProducts(c.add)
+			err = c.cart.AddProductsOpts(c.add, c.quantity, c.option)
 		}
 		if err != nil {
 			return err
@@ -114,9 +155,29 @@ func (c *cartCmd) Run(cmd *cobra.Command, args []string) (err error) {
 		// stave order and return early before order is printed out
 		return c.cart.SaveAndReset()
 	}
+	if c.format != "" {
+		tmpl, err := out.ParseFormat(c.format)
+		if err != nil {
+			return err
+		}
+		return out.PrintFormat(tmpl, out.OrderItems(c.cart.CurrentOrder, c.cart.Menu()))
+	}
 	return out.PrintOrder(c.cart.CurrentOrder, true, c.price)
 }
 
+func (c *cartCmd) importCart(args []string) error {
+	f, err := os.Open(c.importFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+	return c.cart.Import(f, name)
+}
+
 func onlyFailures(e error) error {
 	if e == nil || dawg.IsWarning(e) {
 		return nil
@@ -128,9 +189,10 @@ func onlyFailures(e error) error {
 func NewCartCmd(b cli.Builder) cli.CliCommand {
 	c := &cartCmd{
 		cart:    cart.New(b),
+		gopts:   b.GlobalOptions(),
+		color:   b.Color,
 		price:   false,
 		delete:  false,
-		verbose: false,
 		topping: false,
 	}
 
@@ -138,7 +200,12 @@ func NewCartCmd(b cli.Builder) cli.CliCommand {
 	cmd := c.Cmd()
 
 	cmd.Long = `The cart command gets information on and edit all of the user
-created orders.`
+created orders.
+
+Use --format to print each product in an order with a go template. The
+template is given the product's .Name, .Code, .Price, .Qty, and .Options.
+
+  apizza cart myorder --format '{{.Qty}} x {{.Code}} ${{.Price}}'`
 
 	cmd.PreRunE = cartPreRun()
 	cmd.ValidArgsFunction = c.cart.OrdersCompletion
@@ -150,8 +217,12 @@ created orders.`
 	c.Flags().StringSliceVarP(&c.add, "add", "a", c.add, "add any number of products to a specific order")
 	c.Flags().StringVarP(&c.remove, "remove", "r", c.remove, "remove a product from the order")
 	c.Flags().StringVarP(&c.product, "product", "p", "", "give the product that will be effected by --add or --remove")
-
-	c.Flags().BoolVarP(&c.verbose, "verbose", "v", c.verbose, "print cart verbosely")
+	c.Flags().IntVarP(&c.quantity, "quantity", "q", 1, "the number of each product given to --add")
+	c.Flags().StringVar(&c.option, "option", "", "options for the products given to --add (ex. cheese:1.5,pepperoni:left)")
+	c.Flags().StringSliceVar(&c.coupons, "add-coupon", c.coupons, "add any number of coupon codes to a specific order")
+	c.Flags().StringVar(&c.exportFile, "export", "", "save an order to a json file")
+	c.Flags().StringVar(&c.importFile, "import", "", "create an order from a json file made with --export")
+	c.Flags().StringVar(&c.format, "format", "", "print each product in the order with a go template (ex. '{{.Code}} x{{.Qty}}')")
 
 	c.Addcmd(newAddOrderCmd(b))
 	return c
@@ -174,6 +245,7 @@ type addOrderCmd struct {
 
 	name     string
 	product  string
+	quantity int
 	toppings []string
 }
 
@@ -181,6 +253,9 @@ func (c *addOrderCmd) Run(cmd *cobra.Command, args []string) (err error) {
 	if c.name == "" && len(args) < 1 {
 		return internal.ErrNoOrderName
 	}
+	if c.quantity < 1 {
+		return cart.ErrBadQuantity
+	}
 	order := c.Store().NewOrder()
 
 	if c.name == "" {
@@ -200,7 +275,7 @@ func (c *addOrderCmd) Run(cmd *cobra.Command, args []string) (err error) {
 				return err
 			}
 		}
-		if err = order.AddProduct(prod); err != nil {
+		if err = order.AddProductQty(prod, c.quantity); err != nil {
 			return err
 		}
 	} else if len(c.toppings) > 0 {
@@ -219,30 +294,52 @@ func newAddOrderCmd(b cli.Builder) cli.CliCommand {
 	c.Flags().StringVarP(&c.name, "name", "n", c.name, "set the name of a new order")
 	c.Flags().StringVarP(&c.product, "product", "p", c.product, "product codes for the new order")
 	c.Flags().StringSliceVarP(&c.toppings, "toppings", "t", c.toppings, "toppings for the products being added")
+	c.Flags().IntVarP(&c.quantity, "quantity", "q", 1, "the number of products to add")
 	return c
 }
 
 // `apizza order`
 type orderCmd struct {
 	cli.CliCommand
-	db *cache.DataBase
+	db    *cache.DataBase
+	conf  *cli.Config
+	gopts *opts.CliFlags
 
-	verbose bool
 	track   bool
+	history bool
+	limit   int
+	reorder int
+	cart    *cart.Cart
 
 	email, phone string
 	fname, lname string
 	cvv          int
 	number       string
 	expiration   string
+	pay          string
 
 	logonly    bool
+	tip        float64
+	when       string
+	force      bool
 	getaddress func() dawg.Address
+	getctx     func() context.Context
+	progress   func() *cli.Progress
 }
 
 func (c *orderCmd) Run(cmd *cobra.Command, args []string) (err error) {
+	if c.history {
+		entries, err := data.History(c.db, c.limit)
+		if err != nil {
+			return err
+		}
+		return data.PrintHistory(c.Output(), entries)
+	}
+	if c.reorder != 0 {
+		return c.reorderHistory(args)
+	}
 	if len(args) < 1 {
-		return data.PrintOrders(c.db, c.Output(), c.verbose)
+		return data.PrintOrders(c.db, c.Output(), c.gopts.Verbose > 0)
 	} else if len(args) > 1 {
 		return errors.New("cannot handle multiple orders")
 	}
@@ -254,11 +351,30 @@ func (c *orderCmd) Run(cmd *cobra.Command, args []string) (err error) {
 	if err != nil {
 		return err
 	}
+	if c.gopts.Service != "" {
+		order.ServiceMethod = c.gopts.Service
+	}
+	if err = checkTip(c.tip, order.ServiceMethod); err != nil {
+		return err
+	}
+	order.Tip = c.tip
+	at := time.Now()
+	if c.when != "" {
+		if at, err = c.schedule(order); err != nil {
+			return err
+		}
+	}
+	if !c.force {
+		// scheduled orders only need the store to be open at the scheduled time
+		err = client.CheckStoreHours(c.getctx(), c.db, order.StoreID, order.ServiceMethod, at)
+		if err != nil {
+			return err
+		}
+	}
 
-	order.AddCard(dawg.NewCard(
-		eitherOr(c.number, config.GetString("card.number")),
-		eitherOr(c.expiration, config.GetString("card.expiration")),
-		c.cvv))
+	if err = c.addPayment(order); err != nil {
+		return err
+	}
 
 	names := strings.Split(config.GetString("name"), " ")
 	if len(names) >= 1 {
@@ -267,32 +383,60 @@ func (c *orderCmd) Run(cmd *cobra.Command, args []string) (err error) {
 	if len(names) >= 2 {
 		order.LastName = eitherOr(c.lname, strings.Join(names[1:], " "))
 	}
-	order.Email = eitherOr(c.email, config.GetString("email"))
-	order.Phone = eitherOr(c.phone, config.GetString("phone"))
+	if order.Email, err = cli.NormalizeEmail(eitherOr(c.email, config.GetString("email"))); err != nil {
+		return fmt.Errorf("invalid email: %v", err)
+	}
+	if order.Phone, err = cli.NormalizePhone(eitherOr(c.phone, config.GetString("phone"))); err != nil {
+		return fmt.Errorf("invalid phone: %v", err)
+	}
 	order.Address = dawg.StreetAddrFromAddress(c.getaddress())
 
-	c.Printf("Ordering dominos for %s to %s\n\n", order.ServiceMethod, strings.Replace(obj.AddressFmt(order.Address), "\n", " ", -1))
+	if order.ServiceMethod == dawg.Carryout {
+		c.Printf("Ordering dominos for %s from store %s\n\n", order.ServiceMethod, order.StoreID)
+	} else {
+		c.Printf("Ordering dominos for %s to %s\n\n", order.ServiceMethod, strings.Replace(obj.AddressFmt(order.Address), "\n", " ", -1))
+	}
 
 	if c.logonly {
 		log.Println("logging order:", dawg.OrderToJSON(order))
 		return nil
 	}
+	if c.gopts.DryRun {
+		return c.dryRun(order)
+	}
 
-	if !yesOrNo(os.Stdin, "Would you like to purchase this order? (y/n)") {
+	if !c.gopts.Yes && !yesOrNo(os.Stdin, "Would you like to purchase this order? (y/n)") {
 		return nil
 	}
 
 	c.Printf("sending order '%s'...\n", order.Name())
 	// TODO: save the order id for tracking and give it a timeout of an hour or two.
-	err = order.PlaceOrder()
+	c.progress().Start("waiting for dominos")
+	conf, err := dawg.Place(c.getctx(), order)
+	c.progress().Stop()
 	// logging happens after so any data from placeorder is included
 	log.Println("sending order:", dawg.OrderToJSON(order))
 	if err != nil {
-		return err
+		return orderErr(err)
 	}
 	c.Printf("sent to %s %s\n", order.Address.LineOne(), order.Address.City())
+	c.Printf("order id: %s\n", conf.OrderID)
+	if conf.Tip > 0 {
+		c.Printf("tip:      $%.2f\n", conf.Tip)
+	}
+	c.Printf("total:    $%.2f\n", conf.Total())
+	if !conf.Scheduled.IsZero() {
+		c.Printf("scheduled: %s\n", conf.Scheduled.Format(data.ScheduleFormat))
+	} else {
+		c.Printf("wait:     %s\n", data.FormatEstimate(conf.EstimatedWait))
+	}
+	entry := data.NewHistoryEntry(order)
+	entry.Estimate = conf.EstimatedWait
+	if err = data.SaveHistoryEntry(c.db, entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not save order to history: %v\n", err)
+	}
 
-	if c.verbose {
+	if c.gopts.Verbose > 0 {
 		if order.ServiceMethod == dawg.Delivery {
 			c.Printf("sent by %s to %s %s\n", order.ServiceMethod,
 				order.Address.LineOne(), order.Address.City())
@@ -304,6 +448,224 @@ func (c *orderCmd) Run(cmd *cobra.Command, args []string) (err error) {
 	return nil
 }
 
+// maxSchedule is how far ahead an order can be scheduled with --when
+// when the store profile does not give a limit.
+const maxSchedule = 7 * 24 * time.Hour
+
+// schedule makes the order a scheduled order for the time given to --when
+// and returns the scheduled time in the store's time zone.
+func (c *orderCmd) schedule(order *dawg.Order) (time.Time, error) {
+	now := time.Now()
+	t, err := parseWhen(c.when, now)
+	if err != nil {
+		return t, err
+	}
+	hours, err := client.StoreHours(c.getctx(), c.db, order.StoreID, order.ServiceMethod)
+	if err != nil {
+		return t, err
+	}
+	if err = checkSchedule(t, now, hours.FutureOrders); err != nil {
+		return t, err
+	}
+	order.ScheduleFor(t, hours.FutureOrders.Location())
+	t, _ = order.ScheduledTime()
+	return t, nil
+}
+
+// checkSchedule returns an error if the time t is outside of the window that
+// a store takes scheduled orders for.
+func checkSchedule(t, now time.Time, f dawg.FutureOrders) error {
+	earliest, latest := f.Window()
+	if latest == 0 {
+		latest = maxSchedule
+	}
+	when := t.In(f.Location()).Format(data.ScheduleFormat)
+	switch ahead := t.Sub(now); {
+	case ahead < earliest:
+		return fmt.Errorf("cannot schedule an order for %s, the store only takes orders scheduled at least %s ahead",
+			when, hoursOrDays(earliest))
+	case ahead > latest:
+		return fmt.Errorf("cannot schedule an order for %s, the store only takes orders scheduled up to %s ahead",
+			when, hoursOrDays(latest))
+	}
+	return nil
+}
+
+func hoursOrDays(d time.Duration) string {
+	n, unit := int(d/time.Hour), "hour"
+	if n >= 24 && n%24 == 0 {
+		n, unit = n/24, "day"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", n, unit)
+}
+
+// parseWhen parses the --when flag as either an RFC3339 time or a duration
+// relative to now like "2h" or "+90m". The time has to be in the future.
+func parseWhen(s string, now time.Time) (time.Time, error) {
+	var t time.Time
+	if d, err := time.ParseDuration(strings.TrimPrefix(s, "+")); err == nil {
+		t = now.Add(d)
+	} else if t, err = time.Parse(time.RFC3339, s); err == nil {
+		t = t.In(time.Local)
+	} else {
+		return t, fmt.Errorf("could not understand --when %q, use a time like %s or a duration like 2h30m",
+			s, now.Add(2*time.Hour).Format(time.RFC3339))
+	}
+	t = t.Truncate(time.Minute)
+	if !t.After(now) {
+		return t, fmt.Errorf("cannot schedule an order for %s, the time has already passed", t.Format(data.ScheduleFormat))
+	}
+	return t, nil
+}
+
+// addPayment adds the payment method selected with --pay to the order or the
+// card given by the flags and the config if --pay was not used.
+func (c *orderCmd) addPayment(order *dawg.Order) error {
+	if c.pay == "" {
+		card := dawg.NewCard(
+			eitherOr(c.number, config.GetString("card.number")),
+			eitherOr(c.expiration, config.GetString("card.expiration")),
+			c.cvv)
+		if card == nil {
+			return errors.New("bad card expiration date, use the 'mm/yy' format")
+		}
+		order.AddCard(card)
+		return nil
+	}
+	if c.number != "" || c.expiration != "" {
+		return errors.New("cannot use --pay with --number or --expiration")
+	}
+	p, ok := c.conf.NamedPayment(c.pay)
+	if !ok {
+		names := c.conf.PaymentNames()
+		if len(names) == 0 {
+			return fmt.Errorf("no payment method named '%s', there are no payment methods in the config", c.pay)
+		}
+		return fmt.Errorf("no payment method named '%s', the payment methods are: %s", c.pay, strings.Join(names, ", "))
+	}
+	if p.Ref == "" {
+		return fmt.Errorf("payment method '%s' has no card reference", c.pay)
+	}
+	order.AddSavedCard(&dawg.SavedCard{
+		ID:         p.Ref,
+		CardType:   p.CardType,
+		PostalCode: p.PostalCode,
+		CVV:        strconv.Itoa(c.cvv),
+	})
+	c.Printf("paying with %s\n", p)
+	return nil
+}
+
+// dryRun validates and prices an order then prints the order
+// without sending it.
+func (c *orderCmd) dryRun(order *dawg.Order) error {
+	ctx := c.getctx()
+	progress := c.progress()
+	progress.Start("checking the order")
+	defer progress.Stop()
+	err := dawg.ValidateOrderContext(ctx, order)
+	if dawg.IsFailure(err) {
+		return orderErr(err)
+	}
+	if err != nil && !dawg.IsWarning(err) {
+		return internal.TimeoutErr(err)
+	}
+	price, err := order.PriceContext(ctx)
+	progress.Stop()
+	if err != nil {
+		return internal.TimeoutErr(err)
+	}
+	c.Printf("dry run: order '%s' was not sent\n", order.Name())
+	c.Printf("price: $%.2f\n", price)
+	if order.Tip > 0 {
+		c.Printf("tip:   $%.2f\ntotal: $%.2f\n", order.Tip, price+order.Tip)
+	}
+	if t, ok := order.ScheduledTime(); ok {
+		c.Printf("scheduled: %s\n", t.Format(data.ScheduleFormat))
+	}
+	c.Printf("payload:\n%s\n", redactedJSON(order))
+	return nil
+}
+
+// redactedJSON returns an order as indented json with the payment
+// information removed so that card details are never printed.
+func redactedJSON(o *dawg.Order) string {
+	raw := dawg.Redact([]byte(dawg.OrderToJSON(o)))
+	buf := &bytes.Buffer{}
+	if err := json.Indent(buf, raw, "", "    "); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+// reorderHistory rebuilds an order from the history and saves it in the cart
+// so that it can be sent with 'apizza order <name>'.
+func (c *orderCmd) reorderHistory(args []string) error {
+	entry, err := data.Entry(c.db, c.reorder)
+	if err != nil {
+		return err
+	}
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+	products := make([]cart.ExportProduct, 0, len(entry.Products))
+	for _, p := range entry.Products {
+		products = append(products, cart.ExportProduct{Code: p.Code, Qty: p.Qty, Options: p.Opts})
+	}
+	c.cart.SetOutput(c.Output())
+	order, err := c.cart.Restore(&cart.Export{
+		Name:          entry.Name,
+		ServiceMethod: entry.ServiceMethod,
+		StoreID:       entry.StoreID,
+		Products:      products,
+	}, name)
+	if err != nil {
+		return err
+	}
+	price, err := order.PriceContext(c.getctx())
+	if err != nil {
+		return internal.TimeoutErr(err)
+	}
+	c.Printf("price: $%.2f (was $%.2f)\n", price, entry.Total)
+	c.Printf("send it with 'apizza order %s --cvv=<cvv>'\n", order.Name())
+	return nil
+}
+
+// orderErr explains the errors that dominos sends back when
+// an order is rejected.
+func orderErr(err error) error {
+	switch {
+	case errors.Is(err, dawg.ErrStoreClosed):
+		return fmt.Errorf("the store is not taking orders right now (see 'apizza store'): %w", err)
+	case errors.Is(err, dawg.ErrInvalidAddress):
+		return fmt.Errorf("dominos could not use the address (see 'apizza config validate'): %w", err)
+	case errors.Is(err, dawg.ErrPaymentDeclined):
+		return fmt.Errorf("the payment was declined, check the card number, expiration date, and cvv: %w", err)
+	}
+	return internal.TimeoutErr(err)
+}
+
+// maxTip is the largest tip that can be given with --tip.
+const maxTip = 100.0
+
+func checkTip(tip float64, service string) error {
+	switch {
+	case tip == 0:
+		return nil
+	case math.IsNaN(tip) || tip < 0:
+		return errors.New("the tip must be a positive amount")
+	case tip > maxTip:
+		return fmt.Errorf("a tip of $%.2f is too large, the tip cannot be more than $%.2f", tip, maxTip)
+	case service != dawg.Delivery:
+		return errors.New("a tip can only be given for delivery orders")
+	}
+	return nil
+}
+
 func eitherOr(s1, s2 string) string {
 	if len(s1) == 0 {
 		return s2
@@ -314,22 +676,55 @@ func eitherOr(s1, s2 string) string {
 // NewOrderCmd creates a new order command.
 func NewOrderCmd(b cli.Builder) cli.CliCommand {
 	c := &orderCmd{
-		verbose:    false,
 		getaddress: b.Address,
+		getctx:     b.Context,
+		progress:   b.Progress,
 	}
 	c.CliCommand = b.Build("order", "Send an order from the cart to dominos.", c)
 	c.db = b.DB()
+	c.conf = b.Config()
+	c.cart = cart.New(b)
+	c.gopts = b.GlobalOptions()
 	c.Cmd().Long = `The order command is the final destination for an order. This is where
 the order will be populated with payment information and sent off to dominos.
 
 The --cvv flag must be specified, and the config file will never store the
 cvv. In addition to keeping the cvv safe, payment information will never be
 stored the program cache with orders.
+
+Use --pay to select one of the named payment methods in the config. These
+only store the last four digits of the card and the id that dominos uses to
+reference the card.
+
+Use the global --dry-run flag to validate and price an order and see exactly
+what would be sent to dominos without actually sending it.
+
+The global --yes flag skips the confirmation prompt, so the order is sent and
+the card is charged right away. If --dry-run is also given, the order is not
+sent.
+
+Use --tip to add a tip to a delivery order. The tip is added to the amount
+that is charged to the card.
+
+Use --reorder to put an order from the history back in the cart, --reorder
+alone uses the most recent order and --reorder=2 uses the one before it.
+
+Use --when to schedule the order for later instead of right away. The time
+can be given as an RFC3339 time or as a duration from now like --when=2h30m.
+Orders can be scheduled as far ahead as the store allows, or up to a week
+ahead if the store does not give a limit.
+
+An order will not be sent if the store is closed. Scheduled orders are checked
+against the store hours at the scheduled time. Use --force for stores that take
+orders for later while they are closed.
 `
 	c.Cmd().PreRunE = cartPreRun()
 
 	flags := c.Cmd().Flags()
-	flags.BoolVarP(&c.verbose, "verbose", "v", c.verbose, "output the order command verbosely")
+	flags.BoolVar(&c.history, "history", false, "show the history of orders that have been sent")
+	flags.IntVar(&c.limit, "limit", 10, "the number of orders shown with --history (0 for all)")
+	flags.IntVar(&c.reorder, "reorder", 0, "rebuild the nth most recent order in the history (see --history)")
+	flags.Lookup("reorder").NoOptDefVal = "1"
 
 	flags.StringVar(&c.phone, "phone", "", "Set the phone number that will be used for this order")
 	flags.StringVar(&c.email, "email", "", "Set the email that will be used for this order")
@@ -339,8 +734,12 @@ stored the program cache with orders.
 	flags.IntVar(&c.cvv, "cvv", 0, "Set the card's cvv number for this order")
 	flags.StringVar(&c.number, "number", "", "the card number used for orderings")
 	flags.StringVar(&c.expiration, "expiration", "", "the card's expiration date")
+	flags.StringVar(&c.pay, "pay", "", "the name of a payment method in the config to use for this order")
+	flags.Float64Var(&c.tip, "tip", 0, "add a tip to a delivery order (ex. --tip=3.50)")
+	flags.StringVar(&c.when, "when", "", "schedule the order for a later time (ex. --when=2h or --when=2020-05-01T18:30:00-04:00)")
 
 	flags.BoolVar(&c.logonly, "log-only", false, "")
+	flags.BoolVar(&c.force, "force", false, "send the order even if the store is closed")
 	flags.MarkHidden("log-only")
 	return c
 }
@@ -1,10 +1,12 @@
 package cart
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -19,18 +21,14 @@ import (
 
 // New will create a new cart
 func New(b cli.Builder) *Cart {
-	storefinder := client.NewStoreGetterFunc(func() string {
-		opts := b.GlobalOptions()
-		if opts.Service != "" {
-			return opts.Service
-		}
-		return b.Config().Service
-	}, b.Address)
+	storefinder := client.NewStoreGetter(b)
 
 	return &Cart{
 		db:     b.DB(),
 		finder: storefinder,
+		getctx: b.Context,
 		MenuCacher: data.NewMenuCacher(
+			b.Context,
 			opts.MenuUpdateTime,
 			b.DB(),
 			storefinder.Store,
@@ -46,6 +44,10 @@ var (
 	// ErrOrderNotFound is raised when the cart cannot find the order
 	// the it was asked to get.
 	ErrOrderNotFound = errors.New("could not find that order")
+
+	// ErrBadQuantity is returned when products are added with a
+	// quantity less than one.
+	ErrBadQuantity = errors.New("quantity must be at least 1")
 )
 
 // Cart is an abstraction on the cache.DataBase struct
@@ -54,6 +56,7 @@ type Cart struct {
 	data.MenuCacher
 	db     *cache.DataBase
 	finder client.StoreFinder
+	getctx func() context.Context
 
 	CurrentOrder *dawg.Order
 	out          io.Writer
@@ -169,13 +172,81 @@ func (c *Cart) AddToppings(product string, toppings []string) error {
 
 // AddProducts adds a list of products to the current order
 func (c *Cart) AddProducts(products []string) error {
+	return c.AddProductsQty(products, 1)
+}
+
+// AddProductsQty adds a list of products to the current order with a
+// quantity of n for each product.
+func (c *Cart) AddProductsQty(products []string, n int) error {
+	return c.AddProductsOpts(products, n, "")
+}
+
+// AddProductsOpts adds a list of products to the current order with a
+// quantity of n and the same options for each product. The options are a
+// comma separated list formatted as <topping>:<side>:<amount> where the
+// side and amount are optional (see ParseOptions).
+func (c *Cart) AddProductsOpts(products []string, n int, options string) error {
+	if c.CurrentOrder == nil {
+		return ErrNoCurrentOrder
+	}
+	if n < 1 {
+		return ErrBadQuantity
+	}
+	if err := c.db.UpdateTS(c.CacheKey(), c); err != nil {
+		return err
+	}
+	return addProductsOpts(c.CurrentOrder, c.Menu(), products, n, options)
+}
+
+// AddCoupons will add coupons to the current order. The order is validated
+// before any coupons are added so that a problem with the order is not blamed
+// on a coupon. Then each coupon is sent to the validation endpoint and if
+// dominos rejects it, the rejection message is written to the cart's output
+// and the coupon is not added. The coupons that were accepted stay in the
+// order even if others were rejected.
+func (c *Cart) AddCoupons(codes []string) error {
 	if c.CurrentOrder == nil {
 		return ErrNoCurrentOrder
 	}
-	if err := c.db.UpdateTS("menu", c); err != nil {
+	err := dawg.ValidateOrderContext(c.getctx(), c.CurrentOrder)
+	if err != nil && !dawg.IsWarning(err) {
 		return err
 	}
-	return addProducts(c.CurrentOrder, c.Menu(), products)
+	var rejected []string
+	for _, code := range codes {
+		if hasCoupon(c.CurrentOrder, code) {
+			continue
+		}
+		c.CurrentOrder.AddCoupon(code)
+		err = dawg.ValidateOrderContext(c.getctx(), c.CurrentOrder)
+		if err == nil || dawg.IsWarning(err) {
+			continue
+		}
+		if e := c.CurrentOrder.RemoveCoupon(code); e != nil {
+			return e
+		}
+		if !dawg.IsFailure(err) {
+			return err
+		}
+		fmt.Fprintln(c.out, err)
+		rejected = append(rejected, code)
+	}
+	switch len(rejected) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("coupon '%s' was rejected", rejected[0])
+	}
+	return fmt.Errorf("coupons '%s' were rejected", strings.Join(rejected, "', '"))
+}
+
+func hasCoupon(o *dawg.Order, code string) bool {
+	for _, c := range o.Coupons {
+		if c.Code == code {
+			return true
+		}
+	}
+	return false
 }
 
 // PrintOrders will print out all the orders saved in the database
@@ -201,21 +272,186 @@ func addToppingsToOrder(o *dawg.Order, product string, toppings []string) (err e
 	return nil
 }
 
-func addProducts(o *dawg.Order, menu *dawg.Menu, products []string) (err error) {
-	var itm dawg.Item
-	for _, newP := range products {
-		itm, err = menu.GetVariant(newP)
+func addProducts(o *dawg.Order, menu *dawg.Menu, products []string, qty int) (err error) {
+	return addProductsOpts(o, menu, products, qty, "")
+}
+
+func addProductsOpts(o *dawg.Order, menu *dawg.Menu, products []string, qty int, options string) error {
+	if qty < 1 {
+		return ErrBadQuantity
+	}
+	opts, err := ParseOptions(options)
+	if err != nil {
+		return err
+	}
+	added := make([]*dawg.OrderProduct, 0, len(products))
+	for _, code := range products {
+		v, err := menu.GetVariant(code)
 		if err != nil {
 			return err
 		}
-		err = o.AddProduct(itm)
+		p := dawg.OrderProductFromItem(v)
+		p.Qty = qty
+		if err = addOptions(p, v.FindProduct(menu), menu, opts); err != nil {
+			return err
+		}
+		added = append(added, p)
+	}
+	// only change the order once all of the products are valid
+	o.Products = append(o.Products, added...)
+	return nil
+}
+
+// Option is one topping given to the --option flag.
+type Option struct {
+	// Name is either a topping code or the name of a topping.
+	Name   string
+	Side   string
+	Amount string
+}
+
+// ParseOptions parses a comma separated list of options formatted as
+// <topping>:<side>:<amount>. The side and amount are both optional and can be
+// given in either order, ex. "cheese:1.5,P:left" or "X:right:0.5".
+//
+// The side is one of left, right, or full (also whole) or the portion codes
+// used by dominos (1/2, 2/2, and 1/1). The amount is checked against the
+// product's topping amounts when the options are added.
+func ParseOptions(s string) ([]Option, error) {
+	var opts []Option
+	if s == "" {
+		return opts, nil
+	}
+	for _, raw := range strings.Split(s, ",") {
+		opt, err := parseOption(strings.TrimSpace(raw))
 		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	}
+	return opts, nil
+}
+
+func parseOption(raw string) (Option, error) {
+	parts := strings.Split(raw, ":")
+	if parts[0] == "" || len(parts) > 3 {
+		return Option{}, fmt.Errorf("bad option '%s', use <topping>:<side>:<amount>", raw)
+	}
+	opt := Option{Name: parts[0], Side: dawg.ToppingFull, Amount: "1.0"}
+	for _, p := range parts[1:] {
+		switch strings.ToLower(p) {
+		case "left", dawg.ToppingLeft:
+			opt.Side = dawg.ToppingLeft
+		case "right", dawg.ToppingRight:
+			opt.Side = dawg.ToppingRight
+		case "full", "whole", dawg.ToppingFull:
+			opt.Side = dawg.ToppingFull
+		default:
+			amount, err := strconv.ParseFloat(p, 64)
+			if err != nil || amount < 0 {
+				return Option{}, fmt.Errorf("bad option '%s': '%s' is not a side or an amount", raw, p)
+			}
+			opt.Amount = strconv.FormatFloat(amount, 'f', -1, 64)
+		}
+	}
+	return opt, nil
+}
+
+func validAmount(amount float64, amounts []string) bool {
+	for _, a := range amounts {
+		if f, err := strconv.ParseFloat(a, 64); err == nil && f == amount {
+			return true
+		}
+	}
+	return false
+}
+
+func sideName(side string) string {
+	switch side {
+	case dawg.ToppingLeft:
+		return "left side"
+	case dawg.ToppingRight:
+		return "right side"
+	}
+	return "whole pizza"
+}
+
+// addOptions adds options to an order product after checking that they can
+// be added to the menu product.
+func addOptions(p *dawg.OrderProduct, product *dawg.Product, menu *dawg.Menu, opts []Option) error {
+	if len(opts) == 0 {
+		return nil
+	}
+	if product == nil {
+		return fmt.Errorf("cannot find the options for %s", p.Code)
+	}
+	// copy the options so that the menu's defaults are not changed
+	options := make(map[string]interface{}, len(p.Opts))
+	for k, v := range p.Opts {
+		options[k] = v
+	}
+	p.Opts = options
+
+	toppings := menu.Toppings[product.ProductType]
+	valid := product.ToppingCodes()
+	amounts := product.ToppingAmounts()
+	sides := make(map[string]string) // topping codes to the side they were added on
+	for _, opt := range opts {
+		code, ok := findOption(opt.Name, valid, toppings)
+		if !ok {
+			return fmt.Errorf("'%s' is not an option for %s, valid options are: %s",
+				opt.Name, p.Code, optionList(valid, toppings))
+		}
+		amount, _ := strconv.ParseFloat(opt.Amount, 64)
+		if !validAmount(amount, amounts) {
+			return fmt.Errorf("%s can only be added to %s with the amounts: %s",
+				opt.Name, p.Code, strings.Join(amounts, ", "))
+		}
+		side, half := sides[code]
+		if half && (side == opt.Side || side == dawg.ToppingFull || opt.Side == dawg.ToppingFull) {
+			return fmt.Errorf("%s is already on the %s", opt.Name, sideName(side))
+		}
+		prev, _ := p.Opts[code].(map[string]string)
+		if err := p.AddTopping(code, opt.Side, opt.Amount); err != nil {
 			return err
 		}
+		if half {
+			// keep the other half so that a topping can be on both sides
+			top := p.Opts[code].(map[string]string)
+			for k, v := range prev {
+				top[k] = v
+			}
+		}
+		sides[code] = opt.Side
 	}
 	return nil
 }
 
+// findOption finds the topping code given either a code or a topping name.
+func findOption(name string, valid []string, toppings map[string]dawg.Topping) (string, bool) {
+	for _, code := range valid {
+		if strings.EqualFold(name, code) || strings.EqualFold(name, toppings[code].Name) {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+func optionList(valid []string, toppings map[string]dawg.Topping) string {
+	if len(valid) == 0 {
+		return "none"
+	}
+	list := make([]string, len(valid))
+	for i, code := range valid {
+		if name := toppings[code].Name; name != "" {
+			list[i] = fmt.Sprintf("%s (%s)", code, name)
+		} else {
+			list[i] = code
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
 func getOrderItem(order *dawg.Order, code string) dawg.Item {
 	for _, itm := range order.Products {
 		if itm.ItemCode() == code {
@@ -228,36 +464,16 @@ func getOrderItem(order *dawg.Order, code string) dawg.Item {
 // adds a topping.
 //
 // formated as <name>:<side>:<amount>
-// name is the only one that is required.
+// name is the only one that is required (see ParseOptions).
 func addTopping(topStr string, p dawg.Item) error {
-	var side, amount string
-
-	topping := strings.Split(topStr, ":")
-
-	// assuming strings.Split cannot return zero length array
-	if topping[0] == "" || len(topping) > 3 {
-		return errors.New("incorrect topping format")
-	}
-
-	// TODO: need to check for bed values and use appropriate error handling
-	if len(topping) == 1 {
-		side = dawg.ToppingFull
-	} else if len(topping) >= 2 {
-		side = topping[1]
-		switch strings.ToLower(side) {
-		case "left":
-			side = dawg.ToppingLeft
-		case "right":
-			side = dawg.ToppingRight
-		case "full":
-			side = dawg.ToppingFull
-		}
+	opt, err := parseOption(topStr)
+	if err != nil {
+		return err
 	}
-
-	if len(topping) == 3 {
-		amount = topping[2]
-	} else {
-		amount = "1.0"
+	amount, _ := strconv.ParseFloat(opt.Amount, 64)
+	if !validAmount(amount, dawg.DefaultToppingAmounts) {
+		return fmt.Errorf("the amount of %s must be one of: %s",
+			opt.Name, strings.Join(dawg.DefaultToppingAmounts, ", "))
 	}
-	return p.AddTopping(topping[0], side, amount)
+	return p.AddTopping(opt.Name, opt.Side, opt.Amount)
 }
@@ -1,8 +1,11 @@
 package cart
 
 import (
+	"bytes"
 	"encoding/json"
 	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
@@ -165,8 +168,8 @@ func TestHelpers_Err(t *testing.T) {
 	if m == nil {
 		t.Fatal("nil menu")
 	}
-	tests.Exp(addProducts(o, m, []string{"nope", "not a thing"}))
-	tests.Check(addProducts(o, m, []string{"12SCREEN"}))
+	tests.Exp(addProducts(o, m, []string{"nope", "not a thing"}, 1))
+	tests.Check(addProducts(o, m, []string{"12SCREEN"}, 1))
 	tests.Exp(addToppingsToOrder(o, "nothere", []string{"K", "B"}))
 	tests.Exp(addToppingsToOrder(o, "", []string{"K", "B"}))
 	tests.Exp(addToppingsToOrder(o, "12SCREEN", []string{""}))
@@ -179,3 +182,257 @@ func setup(t *testing.T) (*cmdtest.Recorder, *Cart, *dawg.Order) {
 	cart.SetOutput(ioutil.Discard)
 	return r, cart, cmdtest.NewTestOrder()
 }
+
+func TestExportImport(t *testing.T) {
+	tests.InitHelpers(t)
+	o := cmdtest.NewTestOrder()
+	o.Products = []*dawg.OrderProduct{
+		{ItemCommon: dawg.ItemCommon{Code: "12SCREEN"}, Qty: 2,
+			Opts: map[string]interface{}{"P": map[string]interface{}{"1/1": "1.0"}}},
+		{ItemCommon: dawg.ItemCommon{Code: "OLDITEM"}, Qty: 1},
+	}
+	o.AddCoupon("9193")
+
+	c := &Cart{CurrentOrder: o}
+	buf := &bytes.Buffer{}
+	tests.Check(c.Export(buf))
+	e := &Export{}
+	tests.Check(json.Unmarshal(buf.Bytes(), e))
+	if e.Version != ExportVersion || e.Name != cmdtest.OrderName || e.StoreID != "4336" {
+		t.Errorf("wrong export header: %+v", e)
+	}
+	if len(e.Products) != 2 || e.Products[0].Qty != 2 {
+		t.Fatal("wrong exported products")
+	}
+	if len(e.Coupons) != 1 || e.Coupons[0] != "9193" {
+		t.Error("wrong exported coupons")
+	}
+
+	menu := &dawg.Menu{
+		Products: map[string]*dawg.Product{"S_PIZZA": {}},
+		Variants: map[string]*dawg.Variant{
+			"12SCREEN": {ItemCommon: dawg.ItemCommon{Code: "12SCREEN"}, ProductCode: "S_PIZZA"},
+		},
+	}
+	warn := &bytes.Buffer{}
+	imported := cmdtest.NewTestOrder()
+	importProducts(imported, menu, e.Products, warn)
+	tests.Compare(t, warn.String(), "Warning: 'OLDITEM' is not on the menu, skipping it\n")
+	if len(imported.Products) != 1 {
+		t.Fatal("should have skipped the item that is not on the menu")
+	}
+	p := imported.Products[0]
+	if p.Code != "12SCREEN" || p.Qty != 2 {
+		t.Error("wrong imported product")
+	}
+	if _, ok := p.Opts["P"]; !ok {
+		t.Error("imported product should keep its options")
+	}
+	if (&Cart{}).Export(buf) != ErrNoCurrentOrder {
+		t.Error("expected ErrNoCurrentOrder")
+	}
+}
+
+func TestAddProductsQty(t *testing.T) {
+	tests.InitHelpers(t)
+	menu := &dawg.Menu{
+		Products: map[string]*dawg.Product{"S_PIZZA": {}},
+		Variants: map[string]*dawg.Variant{
+			"12SCREEN": {ItemCommon: dawg.ItemCommon{Code: "12SCREEN"}, ProductCode: "S_PIZZA"},
+		},
+	}
+	o := cmdtest.NewTestOrder()
+	if addProducts(o, menu, []string{"12SCREEN"}, 0) != ErrBadQuantity {
+		t.Error("expected ErrBadQuantity")
+	}
+	tests.Check(addProducts(o, menu, []string{"12SCREEN"}, 3))
+	if len(o.Products) != 1 || o.Products[0].Qty != 3 {
+		t.Fatal("should have added one product with a quantity of 3")
+	}
+
+	b, err := json.Marshal(o)
+	tests.Check(err)
+	stored := &dawg.Order{}
+	tests.Check(json.Unmarshal(b, stored))
+	if stored.Products[0].Qty != 3 {
+		t.Error("quantity should be stored with the order")
+	}
+
+	c := &Cart{CurrentOrder: o}
+	if c.AddProductsQty([]string{"12SCREEN"}, -1) != ErrBadQuantity {
+		t.Error("expected ErrBadQuantity")
+	}
+}
+
+func TestAddProductsOpts(t *testing.T) {
+	tests.InitHelpers(t)
+	menu := &dawg.Menu{
+		Products: map[string]*dawg.Product{
+			"S_PIZZA": {
+				ItemCommon:        dawg.ItemCommon{Code: "S_PIZZA"},
+				ProductType:       "Pizza",
+				AvailableToppings: "X=0:0.5:1:1.5,C=0:0.5:1:1.5:2,P=1/1",
+			},
+		},
+		Variants: map[string]*dawg.Variant{
+			"14SCREEN": {ItemCommon: dawg.ItemCommon{Code: "14SCREEN"}, ProductCode: "S_PIZZA"},
+		},
+		Toppings: map[string]map[string]dawg.Topping{
+			"Pizza": {
+				"X": {ItemCommon: dawg.ItemCommon{Code: "X", Name: "Robust Inspired Tomato Sauce"}},
+				"C": {ItemCommon: dawg.ItemCommon{Code: "C", Name: "Cheese"}},
+				"P": {ItemCommon: dawg.ItemCommon{Code: "P", Name: "Pepperoni"}},
+			},
+		},
+	}
+	o := cmdtest.NewTestOrder()
+	tests.Check(addProductsOpts(o, menu, []string{"14SCREEN"}, 2, "cheese:1.5,p:left"))
+	if len(o.Products) != 1 || o.Products[0].Qty != 2 {
+		t.Fatal("should have added one product with a quantity of 2")
+	}
+	opts := o.Products[0].Opts
+	if top, ok := opts["C"].(map[string]string); !ok || top[dawg.ToppingFull] != "1.5" {
+		t.Errorf("wrong cheese option: %v", opts["C"])
+	}
+	if top, ok := opts["P"].(map[string]string); !ok || top[dawg.ToppingLeft] != "1.0" {
+		t.Errorf("wrong pepperoni option: %v", opts["P"])
+	}
+	if len(menu.Variants["14SCREEN"].Options()) != 0 {
+		t.Error("adding options should not change the menu")
+	}
+
+	err := addProductsOpts(o, menu, []string{"14SCREEN"}, 1, "pineapple")
+	if err == nil {
+		t.Fatal("expected an error for an unknown option")
+	}
+	tests.StrEq(err.Error(), "'pineapple' is not an option for 14SCREEN, valid options are: "+
+		"X (Robust Inspired Tomato Sauce), C (Cheese), P (Pepperoni)", "wrong error message")
+	if len(o.Products) != 1 {
+		t.Error("products with bad options should not be added")
+	}
+
+	for _, bad := range []string{"C:1.5:left:full", ":left", "C:lots"} {
+		if _, err = ParseOptions(bad); err == nil {
+			t.Errorf("expected an error for '%s'", bad)
+		}
+	}
+	parsed, err := ParseOptions("X:right:0.5")
+	tests.Check(err)
+	if len(parsed) != 1 || parsed[0] != (Option{Name: "X", Side: dawg.ToppingRight, Amount: "0.5"}) {
+		t.Errorf("wrong option: %+v", parsed)
+	}
+}
+
+func TestToppingPortions(t *testing.T) {
+	tests.InitHelpers(t)
+	menu := &dawg.Menu{
+		Products: map[string]*dawg.Product{
+			"S_PIZZA": {
+				ItemCommon:        dawg.ItemCommon{Code: "S_PIZZA", Tags: map[string]interface{}{"OptionQtys": []interface{}{"0", "0.5", "1", "1.5", "2"}}},
+				ProductType:       "Pizza",
+				AvailableToppings: "P=1/1,M=1/1",
+			},
+			"S_WINGS": {
+				ItemCommon:        dawg.ItemCommon{Code: "S_WINGS", Tags: map[string]interface{}{"OptionQtys": []interface{}{"0", "1"}}},
+				ProductType:       "Wings",
+				AvailableToppings: "SIDBC=1",
+			},
+		},
+		Variants: map[string]*dawg.Variant{
+			"14SCREEN": {ItemCommon: dawg.ItemCommon{Code: "14SCREEN"}, ProductCode: "S_PIZZA"},
+			"W08PHOTW": {ItemCommon: dawg.ItemCommon{Code: "W08PHOTW"}, ProductCode: "S_WINGS"},
+		},
+		Toppings: map[string]map[string]dawg.Topping{
+			"Pizza": {
+				"P": {ItemCommon: dawg.ItemCommon{Code: "P", Name: "Pepperoni"}},
+				"M": {ItemCommon: dawg.ItemCommon{Code: "M", Name: "Mushrooms"}},
+			},
+		},
+	}
+	o := cmdtest.NewTestOrder()
+	tests.Check(addProductsOpts(o, menu, []string{"14SCREEN"}, 1, "pepperoni:left:1,mushrooms:2/2:2,P:right:0.5"))
+	opts := o.Products[0].Opts
+	if top := opts["P"].(map[string]string); top[dawg.ToppingLeft] != "1.0" || top[dawg.ToppingRight] != "0.5" {
+		t.Errorf("pepperoni should be on both halves: %v", top)
+	}
+	if top := opts["M"].(map[string]string); len(top) != 1 || top[dawg.ToppingRight] != "2.0" {
+		t.Errorf("wrong mushroom option: %v", top)
+	}
+
+	for _, bad := range []string{"P:left,P:left:2", "P:left,P:whole", "P,P:right", "P:3", "M:right:0.25"} {
+		if err := addProductsOpts(o, menu, []string{"14SCREEN"}, 1, bad); err == nil {
+			t.Errorf("expected an error for '%s'", bad)
+		}
+	}
+	err := addProductsOpts(o, menu, []string{"W08PHOTW"}, 1, "SIDBC:1.5")
+	tests.Exp(err)
+	if err != nil {
+		tests.StrEq(err.Error(), "SIDBC can only be added to W08PHOTW with the amounts: 0, 1", "wrong error message")
+	}
+	if len(o.Products) != 1 {
+		t.Error("products with bad options should not be added")
+	}
+
+	for _, bad := range []string{"P:-1", "P:left:full:2", "P:top"} {
+		if _, err = ParseOptions(bad); err == nil {
+			t.Errorf("expected an error for '%s'", bad)
+		}
+	}
+	parsed, err := ParseOptions("P:1/2:2.0,M:whole:0")
+	tests.Check(err)
+	if len(parsed) != 2 || parsed[0] != (Option{Name: "P", Side: dawg.ToppingLeft, Amount: "2"}) ||
+		parsed[1] != (Option{Name: "M", Side: dawg.ToppingFull, Amount: "0"}) {
+		t.Errorf("wrong options: %+v", parsed)
+	}
+	tests.Exp(addTopping("P:middle", testProduct))
+	tests.Exp(addTopping("P:left:3", testProduct))
+}
+
+func TestAddCoupons(t *testing.T) {
+	r, cart, order := setup(t)
+	defer r.CleanUp()
+	srv := cmdtest.NewServer()
+	defer srv.Close()
+	buf := &bytes.Buffer{}
+	cart.SetOutput(buf)
+	cart.CurrentOrder = order
+
+	orderOK := true
+	srv.HandleFunc("/power/validate-order", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch {
+		case !orderOK:
+			w.Write([]byte(`{"Status":-1,"Order":{"Status":-1,"StatusItems":[{"Code":"PosOrderIncomplete"}]}}`))
+		case bytes.Contains(body, []byte(`"BAD`)):
+			w.Write([]byte(`{"Status":-1,"Order":{"Status":-1,"StatusItems":[{"Code":"InvalidCoupon"}]}}`))
+		default:
+			w.Write([]byte(cmdtest.ValidateOrderJSON))
+		}
+	})
+
+	err := cart.AddCoupons([]string{"9193", "BAD1", "5152", "BAD2"})
+	if err == nil || err.Error() != "coupons 'BAD1', 'BAD2' were rejected" {
+		t.Errorf("wrong error: %v", err)
+	}
+	if len(order.Coupons) != 2 || order.Coupons[0].Code != "9193" || order.Coupons[1].Code != "5152" {
+		t.Errorf("the accepted coupons should stay in the order, got %+v", order.Coupons)
+	}
+	if !strings.HasSuffix(buf.String(), "\n") || strings.Count(buf.String(), "InvalidCoupon") != 2 {
+		t.Errorf("each rejection should be printed on its own line, got %q", buf.String())
+	}
+
+	err = cart.AddCoupons([]string{"BAD3"})
+	if err == nil || err.Error() != "coupon 'BAD3' was rejected" {
+		t.Errorf("wrong error: %v", err)
+	}
+
+	// problems with the order are not blamed on the coupons
+	orderOK = false
+	err = cart.AddCoupons([]string{"8000"})
+	if !dawg.IsFailure(err) || strings.Contains(err.Error(), "coupon") {
+		t.Errorf("the order failure should be returned as is, got %v", err)
+	}
+	if len(order.Coupons) != 2 {
+		t.Error("the order should not change when it does not validate")
+	}
+}
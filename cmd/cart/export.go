@@ -0,0 +1,142 @@
+package cart
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+)
+
+// ExportVersion is the version of the cart export file format. It should only
+// be changed if the format changes in a way that breaks older files.
+const ExportVersion = 1
+
+// Export is the file format used to export and import orders from the cart.
+type Export struct {
+	// Version is the version of the file format (see ExportVersion).
+	Version int `json:"version"`
+	// Name is the name of the order in the cart.
+	Name string `json:"name"`
+	// ServiceMethod is either "Delivery" or "Carryout".
+	ServiceMethod string `json:"service_method"`
+	// StoreID is the store that the order was made for. It is only kept
+	// for reference, imported orders will use the current store.
+	StoreID string `json:"store_id"`
+	// Products is the list of products in the order.
+	Products []ExportProduct `json:"products"`
+	// Coupons is a list of coupon codes.
+	Coupons []string `json:"coupons,omitempty"`
+}
+
+// ExportProduct is one product in an exported order.
+type ExportProduct struct {
+	// Code is the menu code of the product variant.
+	Code string `json:"code"`
+	// Qty is the number of this product in the order.
+	Qty int `json:"qty"`
+	// Options holds the product's toppings in the same format that is
+	// sent to dominos ex. {"P": {"1/1": "1.0"}}.
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// NewExport creates an Export from an order.
+func NewExport(o *dawg.Order) *Export {
+	e := &Export{
+		Version:       ExportVersion,
+		Name:          o.Name(),
+		ServiceMethod: o.ServiceMethod,
+		StoreID:       o.StoreID,
+		Products:      make([]ExportProduct, 0, len(o.Products)),
+	}
+	for _, p := range o.Products {
+		e.Products = append(e.Products, ExportProduct{
+			Code:    p.Code,
+			Qty:     p.Qty,
+			Options: p.Opts,
+		})
+	}
+	for _, c := range o.Coupons {
+		e.Coupons = append(e.Coupons, c.Code)
+	}
+	return e
+}
+
+// Export will write the current order to w as json.
+func (c *Cart) Export(w io.Writer) error {
+	if c.CurrentOrder == nil {
+		return ErrNoCurrentOrder
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(NewExport(c.CurrentOrder))
+}
+
+// Import will read an exported order from r and save it to the cart. If name
+// is not empty it will be used instead of the name in the exported file.
+// Products that are no longer on the menu are skipped and a warning is
+// written to the cart's output.
+func (c *Cart) Import(r io.Reader, name string) error {
+	e := &Export{}
+	if err := json.NewDecoder(r).Decode(e); err != nil {
+		return fmt.Errorf("could not read cart file: %v", err)
+	}
+	if e.Version != ExportVersion {
+		return fmt.Errorf("unsupported cart file version %d", e.Version)
+	}
+	if name == "" && e.Name == "" {
+		return errors.New("cart file has no order name, give one as an argument")
+	}
+	_, err := c.Restore(e, name)
+	return err
+}
+
+// Restore will rebuild an exported order for the current store and menu and
+// save it to the cart. If name is empty, the name of the export is used.
+// Products that are no longer on the menu are skipped with a warning but it
+// is an error if none of the products are left.
+func (c *Cart) Restore(e *Export, name string) (*dawg.Order, error) {
+	if name == "" {
+		name = e.Name
+	}
+	if c.db.Exists(data.OrderPrefix + name) {
+		return nil, fmt.Errorf("an order named '%s' already exists", name)
+	}
+	if err := c.db.UpdateTS(c.CacheKey(), c); err != nil {
+		return nil, err
+	}
+
+	order := c.finder.Store().NewOrder()
+	order.SetName(name)
+	if e.ServiceMethod == dawg.Delivery || e.ServiceMethod == dawg.Carryout {
+		order.ServiceMethod = e.ServiceMethod
+	}
+	importProducts(order, c.Menu(), e.Products, c.out)
+	if len(e.Products) > 0 && len(order.Products) == 0 {
+		return nil, errors.New("none of the products are on the menu anymore")
+	}
+	for _, code := range e.Coupons {
+		order.AddCoupon(code)
+	}
+	return order, data.SaveOrder(order, c.out, c.db)
+}
+
+func importProducts(o *dawg.Order, menu *dawg.Menu, products []ExportProduct, warn io.Writer) {
+	for _, p := range products {
+		v, err := menu.GetVariant(p.Code)
+		if err != nil {
+			fmt.Fprintf(warn, "Warning: '%s' is not on the menu, skipping it\n", p.Code)
+			continue
+		}
+		prod := dawg.OrderProductFromItem(v)
+		if p.Qty > 0 {
+			prod.Qty = p.Qty
+		}
+		if p.Options != nil {
+			prod.Opts = p.Options
+		}
+		o.Products = append(o.Products, prod)
+	}
+}
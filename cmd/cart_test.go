@@ -2,12 +2,23 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/internal"
 	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
 	"github.com/harrybrwn/apizza/pkg/tests"
 )
 
@@ -179,6 +190,99 @@ func TestOrder(t *testing.T) {
 	cmd.cvv = 0
 }
 
+func TestOrderPay(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewOrderCmd(r).(*orderCmd)
+	c.cvv = 123
+
+	c.pay = "work"
+	o := cmdtest.NewTestOrder()
+	err := c.addPayment(o)
+	tests.Exp(err)
+	tests.StrEq(err.Error(), "no payment method named 'work', there are no payment methods in the config", "wrong error")
+
+	r.Conf.Payments = map[string]cli.Payment{
+		"work":     {LastFour: "1234", Ref: "card-id", CardType: "Visa"},
+		"personal": {LastFour: "4321"},
+	}
+	tests.Check(c.addPayment(o))
+	r.Compare(t, "paying with Visa ending in 1234\n")
+	if len(o.Payments) != 1 {
+		t.Fatal("should have added one payment")
+	}
+	tests.StrEq(o.Payments[0].CardID, "card-id", "wrong card reference")
+	tests.StrEq(o.Payments[0].Number, "", "should not send a card number")
+	tests.StrEq(o.Payments[0].SecurityCode, "123", "wrong cvv")
+
+	c.pay = "personal"
+	tests.Exp(c.addPayment(o), "should not use a payment without a card reference")
+	c.pay = "nope"
+	err = c.addPayment(o)
+	tests.Exp(err)
+	tests.StrEq(err.Error(), "no payment method named 'nope', the payment methods are: personal, work", "wrong error")
+	c.pay, c.number = "work", "4100123422343234"
+	tests.Exp(c.addPayment(o), "--pay should not be used with --number")
+}
+
+func TestOrderContactInfo(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewOrderCmd(r).(*orderCmd)
+	tests.Check(data.SaveOrder(cmdtest.NewTestOrder(), &bytes.Buffer{}, r.DataBase))
+	c.cvv, c.number, c.expiration = 123, "4100123422343234", "01/30"
+	c.force = true
+
+	c.email, c.phone = "joe@blow", "1231231234"
+	err := c.Run(c.Cmd(), []string{cmdtest.OrderName})
+	tests.Exp(err)
+	tests.StrEq(err.Error(), "invalid email: 'joe@blow' is not a valid email address", "wrong error: %v", err)
+
+	c.email, c.phone = " joe@blow.com ", "123-123-123"
+	err = c.Run(c.Cmd(), []string{cmdtest.OrderName})
+	tests.Exp(err)
+	tests.StrEq(err.Error(), "invalid phone: '123-123-123' should have 10 digits", "wrong error: %v", err)
+}
+
+func TestOrderErr(t *testing.T) {
+	err := orderErr(fmt.Errorf("from dominos: %w", dawg.ErrPaymentDeclined))
+	if !errors.Is(err, dawg.ErrPaymentDeclined) {
+		t.Error("the error should still match the dawg error")
+	}
+	if !strings.HasPrefix(err.Error(), "the payment was declined") {
+		t.Errorf("wrong error: %v", err)
+	}
+	if orderErr(context.DeadlineExceeded) != internal.ErrTimeout {
+		t.Error("should return a timeout error")
+	}
+	other := errors.New("other")
+	if orderErr(other) != other {
+		t.Error("other errors should not be changed")
+	}
+}
+
+func TestCheckTip(t *testing.T) {
+	for _, tip := range []float64{0, 3.5, maxTip} {
+		if err := checkTip(tip, dawg.Delivery); err != nil {
+			t.Errorf("tip of %v should be valid: %v", tip, err)
+		}
+	}
+	for _, tip := range []float64{-1, maxTip + 1, math.NaN()} {
+		if checkTip(tip, dawg.Delivery) == nil {
+			t.Errorf("tip of %v should be invalid", tip)
+		}
+	}
+	err := checkTip(2, dawg.Carryout)
+	if err == nil || err.Error() != "a tip can only be given for delivery orders" {
+		t.Errorf("wrong error: %v", err)
+	}
+	if err = checkTip(0, dawg.Carryout); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestEitherOr(t *testing.T) {
 	if eitherOr("one", "") != "one" {
 		t.Error("wrong result from 'eitherOr'")
@@ -190,3 +294,151 @@ func TestEitherOr(t *testing.T) {
 		t.Error("wrong result from 'eitherOr'")
 	}
 }
+
+func TestParseWhen(t *testing.T) {
+	now := time.Date(2020, time.May, 1, 12, 0, 30, 0, time.Local)
+	for s, exp := range map[string]time.Time{
+		"2h":   time.Date(2020, time.May, 1, 14, 0, 0, 0, time.Local),
+		"+90m": time.Date(2020, time.May, 1, 13, 30, 0, 0, time.Local),
+		"144h": time.Date(2020, time.May, 7, 12, 0, 0, 0, time.Local),
+		now.Add(4 * time.Hour).UTC().Format(time.RFC3339): time.Date(2020, time.May, 1, 16, 0, 0, 0, time.Local),
+	} {
+		when, err := parseWhen(s, now)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+		} else if !when.Equal(exp) {
+			t.Errorf("%q: got %v, want %v", s, when, exp)
+		}
+	}
+	for _, s := range []string{"", "tomorrow", "-1h", "10s", now.Add(-time.Hour).Format(time.RFC3339)} {
+		if _, err := parseWhen(s, now); err == nil {
+			t.Errorf("%q should not be a valid time", s)
+		}
+	}
+}
+
+func TestCheckSchedule(t *testing.T) {
+	tests.InitHelpers(t)
+	now := time.Date(2020, time.May, 1, 12, 0, 0, 0, time.UTC)
+	store := dawg.FutureOrders{TimeZoneMinutes: -240, TimeZoneCode: "GMT-04:00", DelayInHours: 1, MaxDays: 3}
+	tests.Check(checkSchedule(now.Add(2*time.Hour), now, store))
+	tests.Check(checkSchedule(now.Add(72*time.Hour), now, store))
+
+	err := checkSchedule(now.Add(30*time.Minute), now, store)
+	if err == nil || err.Error() != "cannot schedule an order for Friday, May 1 at 8:30am, the store only takes orders scheduled at least 1 hour ahead" {
+		t.Errorf("wrong error for an order that is too soon: %v", err)
+	}
+	err = checkSchedule(now.Add(80*time.Hour), now, store)
+	if err == nil || err.Error() != "cannot schedule an order for Monday, May 4 at 4:00pm, the store only takes orders scheduled up to 3 days ahead" {
+		t.Errorf("wrong error for an order that is too far ahead: %v", err)
+	}
+
+	// stores without limits can take orders up to a week ahead
+	tests.Check(checkSchedule(now.Add(10*time.Minute), now, dawg.FutureOrders{}))
+	tests.Check(checkSchedule(now.Add(144*time.Hour), now, dawg.FutureOrders{}))
+	tests.Exp(checkSchedule(now.Add(200*time.Hour), now, dawg.FutureOrders{}))
+}
+
+func TestCartCoupons(t *testing.T) {
+	tests.InitHelpers(t)
+	srv := cmdtest.NewServer()
+	defer srv.Close()
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	srv.HandleFunc("/power/validate-order", func(w http.ResponseWriter, req *http.Request) {
+		if body, _ := ioutil.ReadAll(req.Body); bytes.Contains(body, []byte(`"BAD"`)) {
+			w.Write([]byte(`{"Status":-1,"Order":{"Status":-1,"StatusItems":[{"Code":"InvalidCoupon"}]}}`))
+			return
+		}
+		w.Write([]byte(cmdtest.ValidateOrderJSON))
+	})
+	tests.Check(data.SaveOrder(cmdtest.NewTestOrder(), &bytes.Buffer{}, r.DataBase))
+
+	c := NewCartCmd(r).(*cartCmd)
+	c.coupons = []string{"9193", "BAD"}
+	err := c.Run(c.Cmd(), []string{cmdtest.OrderName})
+	if err == nil || err.Error() != "coupon 'BAD' was rejected" {
+		t.Errorf("wrong error: %v", err)
+	}
+	o, err := data.GetOrder(cmdtest.OrderName, r.DataBase)
+	tests.Check(err)
+	if len(o.Coupons) != 1 || o.Coupons[0].Code != "9193" {
+		t.Errorf("the accepted coupon should be saved, got %+v", o.Coupons)
+	}
+}
+
+func TestOrderPlace(t *testing.T) {
+	tests.InitHelpers(t)
+	srv := cmdtest.NewServer()
+	defer srv.Close()
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	c := NewOrderCmd(r).(*orderCmd)
+	o := cmdtest.NewTestOrder()
+	o.Products = []*dawg.OrderProduct{{ItemCommon: dawg.ItemCommon{Code: "14SCREEN"}, Qty: 1, Opts: map[string]interface{}{}}}
+	tests.Check(data.SaveOrder(o, &bytes.Buffer{}, r.DataBase))
+	c.cvv, c.number, c.expiration = 123, "4100123422343234", "01/30"
+	c.email, c.phone = "jdoe@example.com", "2025550100"
+	c.gopts.Yes = true
+
+	tests.Check(c.Run(c.Cmd(), []string{cmdtest.OrderName}))
+	for _, s := range []string{"order id: mock-order-id", "total:    $14.83", "wait:     16-26"} {
+		if !r.Contains(s) {
+			t.Errorf("output should contain %q, got:\n%s", s, r.Out.String())
+		}
+	}
+	if srv.Count("/power/place-order") != 1 {
+		t.Errorf("the order should be sent once, requests: %v", srv.Requests())
+	}
+	if !bytes.Contains(srv.Body("/power/place-order"), []byte("jdoe@example.com")) {
+		t.Error("the contact info should be sent with the order")
+	}
+	entries, err := data.History(r.DataBase, 0)
+	tests.Check(err)
+	if len(entries) != 1 || entries[0].Estimate != "16-26" {
+		t.Errorf("the order should be saved in the history: %+v", entries)
+	}
+
+	r.ClearBuf()
+	c.gopts.DryRun = true
+	tests.Check(c.Run(c.Cmd(), []string{cmdtest.OrderName}))
+	c.gopts.DryRun = false
+	if !r.Contains("payload:") || !r.Contains("[REDACTED]") {
+		t.Errorf("the dry run should print the redacted payload, got:\n%s", r.Out.String())
+	}
+	for _, card := range []string{"4100123422343234", "01/30", `"SecurityCode": 123`} {
+		if r.Contains(card) {
+			t.Errorf("the dry run should not print the card details, found %q", card)
+		}
+	}
+
+	r.ClearBuf()
+	c.when = "+3h"
+	tests.Check(c.Run(c.Cmd(), []string{cmdtest.OrderName}))
+	c.when = ""
+	if !r.Contains("scheduled: ") || r.Contains("wait:") {
+		t.Errorf("output should show the scheduled time, got:\n%s", r.Out.String())
+	}
+	if !bytes.Contains(srv.Body("/power/place-order"), []byte(`"FutureOrderTime":"`)) {
+		t.Error("the scheduled time should be sent with the order")
+	}
+	entries, err = data.History(r.DataBase, 1)
+	tests.Check(err)
+	if len(entries) != 1 || entries[0].Scheduled == nil {
+		t.Fatal("the history should have the scheduled time")
+	}
+	// the fake store is in GMT-04:00
+	sent := `"FutureOrderTime":"` + entries[0].Scheduled.In(time.FixedZone("", -4*60*60)).Format(dawg.FutureOrderTimeFormat) + `"`
+	if !bytes.Contains(srv.Body("/power/place-order"), []byte(sent)) {
+		t.Errorf("the scheduled time should be sent in the store's time zone, want %s in %s", sent, srv.Body("/power/place-order"))
+	}
+
+	r.ClearBuf()
+	srv.SetResponse("/power/place-order", http.StatusOK, `{"Status":-1,"Order":{"Status":-1,"StatusItems":[{"Code":"CardDeclined"}]}}`)
+	err = c.Run(c.Cmd(), []string{cmdtest.OrderName})
+	if !errors.Is(err, dawg.ErrPaymentDeclined) {
+		t.Errorf("expected a declined payment, got %v", err)
+	}
+}
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"context"
 	"io"
+	"strings"
 
 	"github.com/harrybrwn/apizza/cmd/opts"
 	"github.com/harrybrwn/apizza/dawg"
@@ -16,6 +18,47 @@ type Builder interface {
 	AddressBuilder
 	Output() io.Writer
 	GlobalOptions() *opts.CliFlags
+
+	// Context returns the context that should be used for
+	// all requests sent to dominos.
+	Context() context.Context
+
+	// Logger returns the logger that is controlled by the
+	// --verbose flag. It should only write to stderr.
+	Logger() *Logger
+
+	// Progress returns the spinner that is shown while waiting
+	// for slow requests. It should only write to stderr.
+	Progress() *Progress
+
+	// Geocoder returns the geocoder used to find the coordinates
+	// of an address when looking for stores.
+	Geocoder() dawg.Geocoder
+
+	// Color returns true if the human readable output written
+	// to Output should be colored (see --color).
+	Color() bool
+}
+
+// ServiceMethod returns the service method given by the --service flag or
+// the one in the config file if the flag was not used.
+func ServiceMethod(b Builder) string {
+	if s := b.GlobalOptions().Service; s != "" {
+		return s
+	}
+	return b.Config().Service
+}
+
+// ParseService will convert a case-insensitive service name to either
+// dawg.Delivery or dawg.Carryout.
+func ParseService(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case "delivery":
+		return dawg.Delivery, nil
+	case "carryout":
+		return dawg.Carryout, nil
+	}
+	return "", dawg.ErrBadService
 }
 
 // CommandBuilder defines an interface for building commands.
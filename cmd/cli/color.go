@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// These are the values that can be given to the --color flag.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// ColorEnabled returns true if output written to w should be colored. In
+// auto mode color is only used when w is a terminal so that color codes
+// never end up in pipes or files.
+func ColorEnabled(mode string, w io.Writer) (bool, error) {
+	switch mode {
+	case ColorAuto, "":
+		f, ok := w.(*os.File)
+		return ok && IsTerminal(f), nil
+	case ColorAlways:
+		return true, nil
+	case ColorNever:
+		return false, nil
+	}
+	return false, fmt.Errorf("--color must be %s, %s, or %s", ColorAuto, ColorAlways, ColorNever)
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestColorEnabled(t *testing.T) {
+	f, err := ioutil.TempFile("", "apizza-color")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	for _, tc := range []struct {
+		mode string
+		exp  bool
+	}{
+		{ColorAuto, false}, // files and buffers are not terminals
+		{"", false},
+		{ColorAlways, true},
+		{ColorNever, false},
+	} {
+		for _, w := range []io.Writer{f, &bytes.Buffer{}} {
+			enabled, err := ColorEnabled(tc.mode, w)
+			if err != nil {
+				t.Error(err)
+			}
+			if enabled != tc.exp {
+				t.Errorf("wrong result for --color=%q with %T", tc.mode, w)
+			}
+		}
+	}
+	if _, err = ColorEnabled("yes", f); err == nil {
+		t.Error("expected an error for a bad --color value")
+	}
+}
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 
 	"github.com/harrybrwn/apizza/cmd/internal/obj"
 	"github.com/harrybrwn/apizza/pkg/config"
@@ -19,6 +21,63 @@ type Config struct {
 		Expiration string `config:"expiration" json:"expiration"`
 	} `config:"card" json:"card"`
 	Service string `config:"service" default:"Delivery" json:"service"`
+
+	// Addresses is a set of named addresses. DefaultAddressName may be
+	// set to one of these names.
+	Addresses map[string]obj.Address `config:"addresses" json:"addresses"`
+
+	// Payments is a set of named payment methods that can be
+	// selected with 'apizza order --pay <name>'.
+	Payments map[string]Payment `config:"payments" json:"payments"`
+
+	// DBPath is the path of the cache database. The default is
+	// used when it is empty.
+	DBPath string `config:"db-path" json:"db-path"`
+}
+
+// NamedAddress will find an address stored in the config by name.
+func (c *Config) NamedAddress(name string) (*obj.Address, bool) {
+	addr, ok := c.Addresses[name]
+	if !ok {
+		return nil, false
+	}
+	return &addr, true
+}
+
+// Payment is a payment method stored in the config. The card number and the
+// cvv are never stored, only the last four digits of the card and the id
+// that dominos uses to reference the card.
+type Payment struct {
+	LastFour   string `config:"last-four" json:"last-four"`
+	Ref        string `config:"ref" json:"ref"`
+	CardType   string `config:"card-type" json:"card-type"`
+	PostalCode string `config:"postal-code" json:"postal-code"`
+}
+
+func (p *Payment) String() string {
+	if p.CardType == "" {
+		return "card ending in " + p.LastFour
+	}
+	return fmt.Sprintf("%s ending in %s", p.CardType, p.LastFour)
+}
+
+// NamedPayment will find a payment method stored in the config by name.
+func (c *Config) NamedPayment(name string) (*Payment, bool) {
+	p, ok := c.Payments[name]
+	if !ok {
+		return nil, false
+	}
+	return &p, true
+}
+
+// PaymentNames returns the sorted names of the payment methods in the config.
+func (c *Config) PaymentNames() []string {
+	names := make([]string, 0, len(c.Payments))
+	for name := range c.Payments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // Get a config variable
@@ -29,9 +88,15 @@ func (c *Config) Get(key string) interface{} {
 // Set a config variable
 func (c *Config) Set(key string, val interface{}) error {
 	if config.FieldName(c, key) == "Service" {
-		if val != "Delivery" && val != "Carryout" {
+		s, ok := val.(string)
+		if !ok {
+			return errors.New("service must be either 'Delivery' or 'Carryout'")
+		}
+		service, err := ParseService(s)
+		if err != nil {
 			return errors.New("service must be either 'Delivery' or 'Carryout'")
 		}
+		val = service
 	}
 	return config.SetField(c, key, val)
 }
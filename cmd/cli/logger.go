@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/harrybrwn/apizza/dawg"
+)
+
+const (
+	// LogRequests is the log level that logs the method, url, and
+	// response status of every request sent to dominos.
+	LogRequests = 1
+
+	// LogBodies is the log level that also logs request and response
+	// bodies with payment information redacted.
+	LogBodies = 2
+)
+
+// Logger is a leveled logger. A message is only written when its level is
+// less than or equal to the logger's level so a level of zero is silent.
+type Logger struct {
+	out   io.Writer
+	level int
+}
+
+// NewLogger creates a new Logger.
+func NewLogger(w io.Writer, level int) *Logger {
+	return &Logger{out: w, level: level}
+}
+
+// Level returns the logger's level.
+func (l *Logger) Level() int {
+	return l.level
+}
+
+// Logf will write a formatted message if level is not above the logger's level.
+func (l *Logger) Logf(level int, format string, v ...interface{}) {
+	if level > l.level || level < 1 {
+		return
+	}
+	fmt.Fprintf(l.out, format, v...)
+}
+
+// LogRequest logs an outgoing request.
+func (l *Logger) LogRequest(req *http.Request, body []byte) {
+	l.Logf(LogRequests, "--> %s %s\n", req.Method, req.URL)
+	if len(body) > 0 {
+		l.Logf(LogBodies, "    %s\n", dawg.Redact(body))
+	}
+}
+
+// LogResponse logs the response to a request.
+func (l *Logger) LogResponse(resp *http.Response, body []byte) {
+	l.Logf(LogRequests, "<-- %s %s\n", resp.Status, resp.Request.URL)
+	if len(body) > 0 {
+		l.Logf(LogBodies, "    %s\n", dawg.Redact(body))
+	}
+}
+
+var _ dawg.RequestLogger = (*Logger)(nil)
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	u, _ := url.Parse("https://order.dominos.com/power/price-order")
+	req := &http.Request{Method: "POST", URL: u}
+	resp := &http.Response{Status: "200 OK", Request: req}
+	body := []byte(`{"Number":"4100123422343234"}`)
+
+	l := NewLogger(buf, 0)
+	l.LogRequest(req, body)
+	l.LogResponse(resp, body)
+	if buf.Len() != 0 {
+		t.Errorf("level zero logger should be silent, got %q", buf.String())
+	}
+
+	l = NewLogger(buf, LogRequests)
+	l.LogRequest(req, body)
+	l.LogResponse(resp, body)
+	exp := "--> POST https://order.dominos.com/power/price-order\n<-- 200 OK https://order.dominos.com/power/price-order\n"
+	if buf.String() != exp {
+		t.Errorf("got %q, want %q", buf.String(), exp)
+	}
+
+	buf.Reset()
+	l = NewLogger(buf, LogBodies)
+	l.LogRequest(req, body)
+	if !strings.Contains(buf.String(), `{"Number":"[REDACTED]"}`) {
+		t.Errorf("expected a redacted body, got %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "4100123422343234") {
+		t.Error("card number should not be logged")
+	}
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	spinFrames   = []rune{'|', '/', '-', '\\'}
+	spinInterval = 100 * time.Millisecond
+)
+
+// Progress is a spinner that is shown while waiting for slow requests to
+// dominos. It should only ever write to stderr so that it never ends up in
+// the command output. A disabled Progress does nothing.
+type Progress struct {
+	w       io.Writer
+	enabled bool
+
+	mu   sync.Mutex
+	msg  string
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewProgress creates a new Progress that writes to w if enabled is true.
+func NewProgress(w io.Writer, enabled bool) *Progress {
+	return &Progress{w: w, enabled: enabled}
+}
+
+// Start shows the spinner with a message until Stop is called. Calling Start
+// while the spinner is already running will only change the message.
+func (p *Progress) Start(msg string) {
+	if p == nil || !p.enabled {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.msg = msg
+	if p.stop != nil {
+		return
+	}
+	p.stop, p.done = make(chan struct{}), make(chan struct{})
+	go p.spin(p.stop, p.done)
+}
+
+// Stop removes the spinner and waits for it to be cleared from the screen.
+func (p *Progress) Stop() {
+	if p == nil || !p.enabled {
+		return
+	}
+	p.mu.Lock()
+	stop, done := p.stop, p.done
+	p.stop, p.done = nil, nil
+	p.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (p *Progress) spin(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(spinInterval)
+	defer ticker.Stop()
+
+	// nothing is written until the first tick so that
+	// quick requests never show the spinner
+	drawn := false
+	for i := 0; ; i++ {
+		select {
+		case <-stop:
+			if drawn {
+				fmt.Fprint(p.w, "\r\033[K")
+			}
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			msg := p.msg
+			p.mu.Unlock()
+			fmt.Fprintf(p.w, "\r\033[K%c %s", spinFrames[i%len(spinFrames)], msg)
+			drawn = true
+		}
+	}
+}
+
+// IsTerminal returns true if the file is a terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+type progressKey struct{}
+
+// WithProgress returns a copy of ctx that carries a progress spinner.
+func WithProgress(ctx context.Context, p *Progress) context.Context {
+	return context.WithValue(ctx, progressKey{}, p)
+}
+
+// ProgressFrom returns the progress spinner carried by ctx or a disabled
+// one if there is none.
+func ProgressFrom(ctx context.Context) *Progress {
+	if p, ok := ctx.Value(progressKey{}).(*Progress); ok && p != nil {
+		return p
+	}
+	return NewProgress(ioutil.Discard, false)
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	defer func(d time.Duration) { spinInterval = d }(spinInterval)
+	spinInterval = time.Millisecond
+
+	buf := &bytes.Buffer{}
+	p := NewProgress(buf, false)
+	p.Start("loading")
+	time.Sleep(5 * time.Millisecond)
+	p.Stop()
+	if buf.Len() != 0 {
+		t.Errorf("disabled progress should not write anything, got %q", buf.String())
+	}
+
+	p = NewProgress(buf, true)
+	p.Stop() // should not block if it was never started
+	p.Start("loading")
+	p.Start("still loading")
+	time.Sleep(10 * time.Millisecond)
+	p.Stop()
+	p.Stop()
+	s := buf.String()
+	if !strings.Contains(s, "still loading") {
+		t.Errorf("progress should show the newest message, got %q", s)
+	}
+	if !strings.HasSuffix(s, "\r\033[K") {
+		t.Errorf("progress should clear the line when stopped, got %q", s)
+	}
+
+	buf.Reset()
+	spinInterval = time.Hour
+	p.Start("again")
+	p.Stop()
+	if buf.Len() != 0 {
+		t.Errorf("nothing should be written before the first tick, got %q", buf.String())
+	}
+
+	var nilp *Progress
+	nilp.Start("nothing")
+	nilp.Stop()
+
+	if ProgressFrom(context.Background()).enabled {
+		t.Error("a context without a progress should give a disabled one")
+	}
+	ctx := WithProgress(context.Background(), p)
+	if ProgressFrom(ctx) != p {
+		t.Error("wrong progress from context")
+	}
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/dawg"
+)
+
+// FieldCheck is the result of validating one of the required config fields.
+// A nil Err means that the field is valid.
+type FieldCheck struct {
+	Field string
+	Err   error
+	// Note is extra information about the check such as
+	// why a field was not checked.
+	Note string
+}
+
+// Validate checks all of the fields that are required to send an order and
+// returns the results in the order name, email, phone, and address. The
+// address is not required for carryout orders.
+func (c *Config) Validate() []FieldCheck {
+	addr := &c.Address
+	if c.Service == dawg.Carryout && c.DefaultAddressName == "" && *addr == (obj.Address{}) {
+		return append(c.checkContacts(), FieldCheck{
+			Field: "address",
+			Note:  "not required for carryout",
+		})
+	}
+	if c.DefaultAddressName != "" {
+		named, ok := c.NamedAddress(c.DefaultAddressName)
+		if !ok {
+			return append(c.checkContacts(), FieldCheck{
+				Field: "address",
+				Err:   fmt.Errorf("no address named '%s'", c.DefaultAddressName),
+			})
+		}
+		addr = named
+	}
+	return append(c.checkContacts(), FieldCheck{Field: "address", Err: ValidateAddress(addr)})
+}
+
+func (c *Config) checkContacts() []FieldCheck {
+	return []FieldCheck{
+		{Field: "name", Err: ValidateName(c.Name)},
+		{Field: "email", Err: ValidateEmail(c.Email)},
+		{Field: "phone", Err: ValidatePhone(c.Phone)},
+	}
+}
+
+var (
+	emailRegex = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	zipRegex   = regexp.MustCompile(`^[0-9]{5}(-[0-9]{4})?$`)
+	stateRegex = regexp.MustCompile(`^[a-zA-Z]{2}$`)
+)
+
+// ValidateName checks that a name has both a first and last name.
+func ValidateName(name string) error {
+	switch len(strings.Fields(name)) {
+	case 0:
+		return errors.New("no name given")
+	case 1:
+		return errors.New("must have a first and last name")
+	}
+	return nil
+}
+
+// ValidateEmail checks that an email address is well formed.
+func ValidateEmail(email string) error {
+	if email == "" {
+		return errors.New("no email given")
+	}
+	if !emailRegex.MatchString(email) {
+		return fmt.Errorf("'%s' is not a valid email address", email)
+	}
+	return nil
+}
+
+// ValidatePhone checks that a phone number has ten digits, optionally
+// preceded by a country code of 1. Spaces, dashes, dots, parentheses and a
+// leading '+' are allowed.
+func ValidatePhone(phone string) error {
+	_, err := NormalizePhone(phone)
+	return err
+}
+
+// NormalizePhone validates a phone number (see ValidatePhone) and returns
+// only its ten digits without the country code, which is the format that
+// dominos expects.
+func NormalizePhone(phone string) (string, error) {
+	if phone == "" {
+		return "", errors.New("no phone number given")
+	}
+	var digits []rune
+	for _, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = append(digits, r)
+		case strings.ContainsRune(" -.()+", r):
+		default:
+			return "", fmt.Errorf("'%s' has an invalid character '%c'", phone, r)
+		}
+	}
+	if len(digits) == 11 && digits[0] == '1' {
+		digits = digits[1:]
+	}
+	if len(digits) != 10 {
+		return "", fmt.Errorf("'%s' should have 10 digits", phone)
+	}
+	return string(digits), nil
+}
+
+// NormalizeEmail validates an email address (see ValidateEmail) after
+// removing any surrounding whitespace.
+func NormalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	return email, ValidateEmail(email)
+}
+
+// ValidateAddress checks that an address has a street, city, state code,
+// and zipcode.
+func ValidateAddress(addr *obj.Address) error {
+	// obj.AddrIsEmpty is not used because Address.Zip will panic on
+	// zipcodes with spaces
+	if addr == nil || *addr == (obj.Address{}) {
+		return errors.New("no address given")
+	}
+	var problems []string
+	if strings.TrimSpace(addr.Street) == "" {
+		problems = append(problems, "no street")
+	}
+	if strings.TrimSpace(addr.CityName) == "" {
+		problems = append(problems, "no city")
+	}
+	if !stateRegex.MatchString(addr.State) {
+		problems = append(problems, fmt.Sprintf("bad state code '%s'", addr.State))
+	}
+	if !zipRegex.MatchString(addr.Zipcode) {
+		problems = append(problems, fmt.Sprintf("bad zipcode '%s'", addr.Zipcode))
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, ", "))
+	}
+	return nil
+}
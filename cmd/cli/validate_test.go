@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/dawg"
+)
+
+func TestValidators(t *testing.T) {
+	for _, phone := range []string{"1231231234", "123-123-1234", "+1 (123) 123.1234"} {
+		if err := ValidatePhone(phone); err != nil {
+			t.Errorf("phone %q should be valid: %v", phone, err)
+		}
+	}
+	for _, phone := range []string{"", "123123123", "21231231234", "123-123-123x"} {
+		if ValidatePhone(phone) == nil {
+			t.Errorf("phone %q should be invalid", phone)
+		}
+	}
+	for phone, exp := range map[string]string{
+		"1231231234":        "1231231234",
+		"(123) 123-1234":    "1231231234",
+		"+1 (123) 123.1234": "1231231234",
+	} {
+		p, err := NormalizePhone(phone)
+		if err != nil {
+			t.Error(err)
+		}
+		if p != exp {
+			t.Errorf("NormalizePhone(%q) gave %q, want %q", phone, p, exp)
+		}
+	}
+	if e, err := NormalizeEmail("  joe@blow.com\n"); err != nil || e != "joe@blow.com" {
+		t.Errorf("bad email normalization: %q, %v", e, err)
+	}
+	for _, email := range []string{"", "joe", "joe@blow", "joe @blow.com"} {
+		if ValidateEmail(email) == nil {
+			t.Errorf("email %q should be invalid", email)
+		}
+	}
+	if err := ValidateEmail("joe.blow@mail.co.uk"); err != nil {
+		t.Error(err)
+	}
+	if ValidateName("") == nil || ValidateName("joe") == nil {
+		t.Error("expected an error for an incomplete name")
+	}
+
+	addr := &obj.Address{Street: "1600 Pennsylvania Ave NW", CityName: "Washington", State: "DC", Zipcode: "20500"}
+	if err := ValidateAddress(addr); err != nil {
+		t.Error(err)
+	}
+	if ValidateAddress(&obj.Address{}) == nil {
+		t.Error("an empty address should be invalid")
+	}
+	addr.State, addr.Zipcode = "DCA", "20 500"
+	err := ValidateAddress(addr)
+	if err == nil || err.Error() != "bad state code 'DCA', bad zipcode '20 500'" {
+		t.Errorf("wrong error: %v", err)
+	}
+
+	c := &Config{DefaultAddressName: "nowhere"}
+	checks := c.Validate()
+	if len(checks) != 4 || checks[3].Field != "address" || checks[3].Err == nil {
+		t.Error("a missing named address should be invalid")
+	}
+}
+
+func TestValidateCarryout(t *testing.T) {
+	c := &Config{Name: "Joe Blow", Email: "joe@blow.com", Phone: "1231231234", Service: dawg.Carryout}
+	for _, check := range c.Validate() {
+		if check.Err != nil {
+			t.Errorf("%s: %v", check.Field, check.Err)
+		}
+	}
+	if c.Validate()[3].Note == "" {
+		t.Error("should note why the address was not checked")
+	}
+	c.Service = dawg.Delivery
+	if c.Validate()[3].Err == nil {
+		t.Error("delivery orders need an address")
+	}
+	c.Service = dawg.Carryout
+	c.Address.Zipcode = "1"
+	if c.Validate()[3].Err == nil {
+		t.Error("a bad address should still be invalid for carryout")
+	}
+}
+
+func TestParseService(t *testing.T) {
+	for in, exp := range map[string]string{
+		"carryout": dawg.Carryout, "Carryout": dawg.Carryout,
+		"DELIVERY": dawg.Delivery, "delivery": dawg.Delivery,
+	} {
+		s, err := ParseService(in)
+		if err != nil || s != exp {
+			t.Errorf("ParseService(%q) = %q, %v", in, s, err)
+		}
+	}
+	if _, err := ParseService("pickup"); err != dawg.ErrBadService {
+		t.Error("expected dawg.ErrBadService")
+	}
+	c := &Config{}
+	if err := c.Set("service", "carryout"); err != nil || c.Service != dawg.Carryout {
+		t.Error("should be able to set a lowercase service")
+	}
+	if c.Set("service", "pickup") == nil {
+		t.Error("expected an error for a bad service")
+	}
+}
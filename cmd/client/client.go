@@ -18,7 +18,7 @@ func FromBuilder(b cli.Builder, menuDecay time.Duration) Client {
 	finder := NewStoreGetter(b)
 	return &client{
 		StoreFinder: finder,
-		MenuCacher:  data.NewMenuCacher(menuDecay, b.DB(), finder.Store),
+		MenuCacher:  data.NewMenuCacher(b.Context, menuDecay, b.DB(), finder.Store),
 	}
 }
 
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harrybrwn/apizza/cmd/internal"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+// CheckStoreHours returns an error if a store is closed for a service method
+// at the time t. The store hours are cached and they are only requested from
+// dominos when they are not in the database. The hours are in the store's
+// local time so t is converted to the store's time zone before it is checked.
+func CheckStoreHours(ctx context.Context, db *cache.DataBase, id, service string, t time.Time) error {
+	hours, err := StoreHours(ctx, db, id, service)
+	if err != nil {
+		return err
+	}
+	// the hours are in the store's local time
+	return checkHours(hours.For(service), id, service, t.In(hours.FutureOrders.Location()))
+}
+
+// StoreHours gets the hours of a store from the database or from dominos if
+// they are not cached.
+func StoreHours(ctx context.Context, db *cache.DataBase, id, service string) (*data.StoreHours, error) {
+	hours, err := data.GetStoreHours(db, id)
+	if err != nil || hours != nil {
+		return hours, err
+	}
+	store, err := dawg.NewStoreContext(ctx, id, service, nil)
+	if err != nil {
+		return nil, internal.TimeoutErr(err)
+	}
+	if err = data.SaveStoreHours(db, store); err != nil {
+		return nil, err
+	}
+	return data.HoursFromStore(store), nil
+}
+
+func checkHours(h *dawg.StoreHours, id, service string, t time.Time) error {
+	next, ok := h.NextOpen(t)
+	if !ok || h.IsOpenAt(t) {
+		// stores with no hours are not checked
+		return nil
+	}
+	return &closedError{fmt.Sprintf(
+		"store %s is closed for %s, it opens again %s (use --force to ignore the store hours)",
+		id, strings.ToLower(service), next.Format("Monday at 3:04pm"))}
+}
+
+// closedError is returned when the store hours say that a store is
+// closed. It matches dawg.ErrStoreClosed.
+type closedError struct {
+	msg string
+}
+
+func (e *closedError) Error() string {
+	return e.msg
+}
+
+func (e *closedError) Is(target error) bool {
+	return target == dawg.ErrStoreClosed
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+)
+
+func TestCheckStoreHours(t *testing.T) {
+	db := cmdtest.TempDB()
+	defer db.Destroy()
+	store := &dawg.Store{ID: "4336"}
+	err := json.Unmarshal([]byte(`{
+		"Hours":{"Mon":[{"OpenTime":"10:00","CloseTime":"23:00"}]},
+		"ServiceHours":{"Delivery":{"Mon":[{"OpenTime":"11:00","CloseTime":"22:00"}]}}
+	}`), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = data.SaveStoreHours(db, store); err != nil {
+		t.Fatal(err)
+	}
+	// 2020-04-13 is a monday
+	at := time.Date(2020, time.April, 13, 10, 30, 0, 0, time.Local)
+
+	// the hours are cached so the context is never used
+	ctx := context.Background()
+	if err = CheckStoreHours(ctx, db, "4336", dawg.Carryout, at); err != nil {
+		t.Error(err)
+	}
+	err = CheckStoreHours(ctx, db, "4336", dawg.Delivery, at)
+	exp := "store 4336 is closed for delivery, it opens again Monday at 11:00am (use --force to ignore the store hours)"
+	if err == nil || err.Error() != exp {
+		t.Errorf("wrong error: %v", err)
+	}
+	if !errors.Is(err, dawg.ErrStoreClosed) {
+		t.Error("a closed store should match dawg.ErrStoreClosed")
+	}
+	if err = checkHours(&dawg.StoreHours{}, "4336", dawg.Delivery, at); err != nil {
+		t.Error("stores without hours should not be closed")
+	}
+}
+
+func TestCheckStoreHoursTimeZone(t *testing.T) {
+	db := cmdtest.TempDB()
+	defer db.Destroy()
+	store := &dawg.Store{ID: "4336"}
+	err := json.Unmarshal([]byte(`{
+		"Hours":{"Mon":[{"OpenTime":"10:00","CloseTime":"23:00"}]},
+		"TimeZoneMinutes":-240,"TimeZoneCode":"GMT-04:00"
+	}`), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = data.SaveStoreHours(db, store); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	// 2020-04-13 is a monday, 13:00 UTC is 9:00am at the store
+	err = CheckStoreHours(ctx, db, "4336", dawg.Carryout, time.Date(2020, time.April, 13, 13, 0, 0, 0, time.UTC))
+	if !errors.Is(err, dawg.ErrStoreClosed) {
+		t.Errorf("the store should be closed at 9:00am store time, got %v", err)
+	}
+	// 02:30 UTC on tuesday is still 10:30pm monday at the store
+	err = CheckStoreHours(ctx, db, "4336", dawg.Carryout, time.Date(2020, time.April, 14, 2, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Errorf("the store should be open at 10:30pm store time, got %v", err)
+	}
+}
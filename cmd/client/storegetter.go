@@ -1,10 +1,16 @@
 package client
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	"github.com/harrybrwn/apizza/cmd/cli"
 	"github.com/harrybrwn/apizza/cmd/internal"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
 	"github.com/harrybrwn/apizza/cmd/internal/obj"
 	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
 	"github.com/harrybrwn/apizza/pkg/errs"
 )
 
@@ -21,46 +27,159 @@ type StoreFinder interface {
 // storegetter is meant to be a mixin for any struct that needs to be able to
 // get a store.
 type storegetter struct {
-	getaddr   func() dawg.Address
-	getmethod func() string
-	dstore    *dawg.Store
+	getctx     func() context.Context
+	getstoreid func() string
+	getaddr    func() dawg.Address
+	getmethod  func() string
+	geocoder   func() dawg.Geocoder
+	getdb      func() *cache.DataBase
+	dstore     *dawg.Store
 }
 
 // NewStoreGetter will create a new storefinder.
 func NewStoreGetter(builder cli.Builder) StoreFinder {
 	return &storegetter{
 		getmethod: func() string {
-			return builder.Config().Service
+			return cli.ServiceMethod(builder)
+		},
+		getstoreid: func() string {
+			return builder.GlobalOptions().StoreID
 		},
-		getaddr: builder.Address,
-		dstore:  nil,
+		getctx:   builder.Context,
+		getaddr:  builder.Address,
+		geocoder: builder.Geocoder,
+		getdb:    builder.DB,
+		dstore:   nil,
 	}
 }
 
-// NewStoreGetterFunc creates a new store getter from two funcs
-func NewStoreGetterFunc(service func() string, addr func() dawg.Address) StoreFinder {
+// NewStoreGetterFunc creates a new store getter from a context getter, a
+// store id getter, a service getter, an address getter, a geocoder getter,
+// and a database getter. If the store id getter returns an id, that store
+// will be used instead of searching for the store nearest to the address.
+func NewStoreGetterFunc(
+	ctx func() context.Context,
+	storeID func() string,
+	service func() string,
+	addr func() dawg.Address,
+	geocoder func() dawg.Geocoder,
+	db func() *cache.DataBase,
+) StoreFinder {
 	return &storegetter{
-		getmethod: service,
-		getaddr:   addr,
-		dstore:    nil,
+		getctx:     ctx,
+		getstoreid: storeID,
+		getmethod:  service,
+		getaddr:    addr,
+		geocoder:   geocoder,
+		getdb:      db,
+		dstore:     nil,
 	}
 }
 
 func (s *storegetter) Store() *dawg.Store {
+	if s.dstore == nil && s.getstoreid() != "" {
+		var err error
+		progress := cli.ProgressFrom(s.getctx())
+		progress.Start(fmt.Sprintf("getting store %s", s.getstoreid()))
+		s.dstore, err = storeByID(s.getctx(), s.getstoreid(), s.getmethod(), s.getaddr())
+		progress.Stop()
+		if err != nil {
+			errs.StopNow(err, "Store Error", 1) // will exit
+		}
+	}
 	if s.dstore == nil {
 		var err error
 		var address = s.getaddr()
 		if obj.AddrIsEmpty(address) {
 			errs.StopNow(errs.New(internal.ErrNoAddress), "Error", 1)
 		}
-		s.dstore, err = dawg.NearestStore(address, s.getmethod())
+		if s.dstore = s.savedStore(address); s.dstore != nil {
+			return s.dstore
+		}
+		located := GeocodeAddress(s.getctx(), s.geocoder(), address)
+		progress := cli.ProgressFrom(s.getctx())
+		progress.Start("finding the nearest store")
+		s.dstore, err = dawg.NearestStoreContext(s.getctx(), located, s.getmethod())
+		progress.Stop()
 		if err != nil {
+			err = internal.TimeoutErr(err)
 			errs.StopNow(err, "Store Find Error", 1) // will exit
 		}
+		if db := s.db(); db != nil {
+			if err = data.SaveStore(db, s.dstore.ID, address); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not save the store: %v\n", err)
+			}
+		}
 	}
 	return s.dstore
 }
 
+// savedStore gets the store that was cached for the address. It returns
+// nil if there is no cached store or the store cannot be used anymore.
+func (s *storegetter) savedStore(addr dawg.Address) *dawg.Store {
+	db := s.db()
+	if db == nil {
+		return nil
+	}
+	id, err := data.StoreFor(db, addr)
+	if err != nil || id == "" {
+		return nil
+	}
+	progress := cli.ProgressFrom(s.getctx())
+	progress.Start(fmt.Sprintf("getting store %s", id))
+	store, err := storeByID(s.getctx(), id, s.getmethod(), addr)
+	progress.Stop()
+	if err == internal.ErrTimeout {
+		errs.StopNow(err, "Store Error", 1) // will exit
+	} else if err != nil {
+		// the store may have closed or stopped taking online
+		// orders so the nearest store is found again
+		data.ForgetStore(db)
+		return nil
+	}
+	return store
+}
+
+func (s *storegetter) db() *cache.DataBase {
+	if s.getdb == nil {
+		return nil
+	}
+	return s.getdb()
+}
+
+// GeocodeAddress uses a geocoder to give an address coordinates so that
+// the distances to stores are measured from the address. A warning is
+// printed and the address is returned as is if the geocoder fails.
+func GeocodeAddress(ctx context.Context, g dawg.Geocoder, addr dawg.Address) dawg.Address {
+	located, err := dawg.Geocode(ctx, g, addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not geocode the address: %v\n", err)
+	}
+	return located
+}
+
+// storeByID gets a store from its id and checks that it is accepting orders.
+func storeByID(ctx context.Context, id, service string, addr dawg.Address) (*dawg.Store, error) {
+	store, err := dawg.NewStoreContext(ctx, id, service, addr)
+	if err != nil {
+		if err = internal.TimeoutErr(err); err == internal.ErrTimeout {
+			return nil, err
+		}
+		return nil, fmt.Errorf("could not get store %s: %v", id, err)
+	}
+	return store, checkStore(store, id)
+}
+
+func checkStore(store *dawg.Store, id string) error {
+	if store.ID != id {
+		return fmt.Errorf("store %s does not exist (see 'apizza store' for nearby stores)", id)
+	}
+	if !store.IsOnlineNow {
+		return fmt.Errorf("store %s is not accepting online orders right now", id)
+	}
+	return nil
+}
+
 func (s *storegetter) Address() dawg.Address {
 	return s.getaddr()
 }
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/dawg"
+)
+
+func TestCheckStore(t *testing.T) {
+	if err := checkStore(&dawg.Store{ID: "4336", IsOnlineNow: true}, "4336"); err != nil {
+		t.Error(err)
+	}
+	err := checkStore(&dawg.Store{}, "0000")
+	if err == nil || err.Error() != "store 0000 does not exist (see 'apizza store' for nearby stores)" {
+		t.Errorf("wrong error: %v", err)
+	}
+	err = checkStore(&dawg.Store{ID: "4336", IsOnlineNow: false}, "4336")
+	if err == nil || err.Error() != "store 4336 is not accepting online orders right now" {
+		t.Errorf("wrong error: %v", err)
+	}
+}
+
+type testGeocoder struct{ err error }
+
+func (g *testGeocoder) Geocode(ctx context.Context, addr dawg.Address) (dawg.Coordinates, error) {
+	return dawg.Coordinates{Latitude: 1, Longitude: 2}, g.err
+}
+
+func TestGeocodeAddress(t *testing.T) {
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	r.Geo = &testGeocoder{}
+	sg := NewStoreGetter(r).(*storegetter)
+	if sg.geocoder() != r.Geo {
+		t.Error("the store getter should use the builder's geocoder")
+	}
+
+	addr := GeocodeAddress(context.Background(), sg.geocoder(), r.Address())
+	if l, ok := addr.(dawg.Locator); !ok {
+		t.Error("the address should have coordinates")
+	} else if c, _ := l.Coordinates(); c.Longitude != 2 {
+		t.Error("wrong coordinates")
+	}
+	addr = GeocodeAddress(context.Background(), &testGeocoder{err: errors.New("no")}, r.Address())
+	if addr != r.Address() {
+		t.Error("a failed geocode should give back the same address")
+	}
+}
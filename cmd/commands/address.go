@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/harrybrwn/apizza/cmd/cli"
@@ -73,42 +74,49 @@ type reader struct {
 
 func (a *addAddressCmd) newAddress() error {
 	r := reader{bufio.NewReader(a.in)}
-	addr := obj.Address{}
 
 	a.Printf("Address Name: ")
 	name, err := r.readline()
 	if err != nil {
 		return err
 	}
-	a.Printf("Street Address: ")
-	addr.Street, err = r.readline()
-	if err != nil {
-		return err
-	}
-	a.Printf("City: ")
-	addr.CityName, err = r.readline()
-	if err != nil {
-		return err
-	}
-	a.Printf("State Code: ")
-	addr.State, err = r.readline()
-	if err != nil {
-		return err
-	}
-	a.Printf("Zipcode: ")
-	addr.Zipcode, err = r.readline()
+	addr, err := readAddress(r, a.Printf)
 	if err != nil {
 		return err
 	}
 
 	fmt.Print(name, ":\n", addr, "\n")
-	raw, err := obj.AsGob(&addr)
+	raw, err := obj.AsGob(addr)
 	if err != nil {
 		return err
 	}
 	return a.db.WithBucket("addresses").Put(name, raw)
 }
 
+// readAddress will prompt for each of the address fields.
+func readAddress(r reader, printf func(string, ...interface{})) (*obj.Address, error) {
+	var err error
+	addr := &obj.Address{}
+
+	printf("Street Address: ")
+	if addr.Street, err = r.readline(); err != nil {
+		return nil, err
+	}
+	printf("City: ")
+	if addr.CityName, err = r.readline(); err != nil {
+		return nil, err
+	}
+	printf("State Code: ")
+	if addr.State, err = r.readline(); err != nil {
+		return nil, err
+	}
+	printf("Zipcode: ")
+	if addr.Zipcode, err = r.readline(); err != nil {
+		return nil, err
+	}
+	return addr, nil
+}
+
 func (r *reader) readline() (string, error) {
 	lineone, err := r.scanner.ReadString('\n')
 	if err != nil {
@@ -116,3 +124,85 @@ func (r *reader) readline() (string, error) {
 	}
 	return strings.Trim(lineone, "\n \t\r"), nil
 }
+
+// `apizza config address`
+type configAddressCmd struct {
+	cli.CliCommand
+	conf *cli.Config
+	in   io.Reader
+}
+
+func newConfigAddressCmd(b cli.Builder, in io.Reader) *configAddressCmd {
+	c := &configAddressCmd{conf: b.Config(), in: in}
+	c.CliCommand = b.Build("address", "Manage the named addresses stored in the config file", c)
+	c.Cmd().Aliases = []string{"addr"}
+	c.Cmd().Long = `The 'config address' command manages the named addresses that are
+stored in the config file. The address named by the 'default-address-name'
+config field will be used when no address is given with the --address flag.`
+
+	add := b.Build("add <name>", "Add a named address to the config file", cli.RunFunction(c.add))
+	add.Cmd().Args = cobra.ExactArgs(1)
+	use := b.Build("use <name>", "Set the default address", cli.RunFunction(c.use))
+	use.Cmd().Args = cobra.ExactArgs(1)
+	use.Cmd().ValidArgsFunction = c.namesCompletion
+	c.Addcmd(add, use)
+	return c
+}
+
+func (c *configAddressCmd) Run(cmd *cobra.Command, args []string) error {
+	if len(c.conf.Addresses) == 0 {
+		c.Println("No addresses in config (see 'apizza config address add')")
+		return nil
+	}
+	names := make([]string, 0, len(c.conf.Addresses))
+	for name := range c.conf.Addresses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		addr := c.conf.Addresses[name]
+		if name == c.conf.DefaultAddressName {
+			name += " (default)"
+		}
+		c.Printf("%s:\n  %s\n", name, obj.AddressFmtIndent(&addr, 2))
+	}
+	return nil
+}
+
+func (c *configAddressCmd) add(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	addr, err := readAddress(reader{bufio.NewReader(c.in)}, c.Printf)
+	if err != nil {
+		return err
+	}
+	if c.conf.Addresses == nil {
+		c.conf.Addresses = make(map[string]obj.Address)
+	}
+	c.conf.Addresses[name] = *addr
+	if c.conf.DefaultAddressName == "" {
+		c.conf.DefaultAddressName = name
+	}
+	return nil
+}
+
+func (c *configAddressCmd) use(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if _, ok := c.conf.NamedAddress(name); !ok {
+		return fmt.Errorf("could not find an address named '%s'", name)
+	}
+	c.conf.DefaultAddressName = name
+	return nil
+}
+
+func (c *configAddressCmd) namesCompletion(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	names := make([]string, 0, len(c.conf.Addresses))
+	for name := range c.conf.Addresses {
+		names = append(names, name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/opts"
+	"github.com/harrybrwn/apizza/pkg/cache"
+	"github.com/spf13/cobra"
+)
+
+// NewCacheCmd creates the 'cache' command.
+func NewCacheCmd(b cli.Builder, in io.Reader) cli.CliCommand {
+	c := &cacheCmd{db: b.DB(), in: in, prompt: os.Stderr, gopts: b.GlobalOptions()}
+	c.CliCommand = b.Build("cache", "Manage the cache database", c)
+	c.SetOutput(b.Output())
+	c.Cmd().Long = `The 'cache' command manages the database where menus, orders,
+and other data are cached. Running it with no sub-command will show the
+path to the database.
+
+Use 'apizza cache clear' to delete all of the cached data, this includes the
+orders in the cart. Saved addresses and the order history are not deleted.
+Use 'apizza cache prune' to only delete the values that have expired.
+Use the global --yes flag to skip the confirmation prompt.`
+
+	clear := b.Build("clear", "Delete all of the cached data", cli.RunFunction(c.clear))
+	clear.Cmd().Args = cobra.NoArgs
+	prune := b.Build("prune", "Delete the cached data that has expired", cli.RunFunction(c.prune))
+	prune.Cmd().Args = cobra.NoArgs
+	c.Addcmd(clear, prune)
+	return c
+}
+
+type cacheCmd struct {
+	cli.CliCommand
+	db     *cache.DataBase
+	in     io.Reader
+	prompt io.Writer // confirmation prompts are kept out of the command output
+	gopts  *opts.CliFlags
+}
+
+func (c *cacheCmd) Run(cmd *cobra.Command, args []string) error {
+	c.Println(c.db.Path())
+	return nil
+}
+
+func (c *cacheCmd) clear(cmd *cobra.Command, args []string) error {
+	if !c.confirm("Delete all of the cached data, including the orders in the cart?") {
+		return nil
+	}
+	n, err := c.db.Clear()
+	if err != nil {
+		return err
+	}
+	c.Printf("removed %d %s\n", n, entries(n))
+	return nil
+}
+
+func (c *cacheCmd) prune(cmd *cobra.Command, args []string) error {
+	if !c.confirm("Delete all of the expired cache data?") {
+		return nil
+	}
+	n, err := c.db.Prune()
+	if err != nil {
+		return err
+	}
+	c.Printf("removed %d expired %s\n", n, entries(n))
+	return nil
+}
+
+func (c *cacheCmd) confirm(msg string) bool {
+	if c.gopts.Yes {
+		return true
+	}
+	fmt.Fprintf(c.prompt, "%s (y/n) ", msg)
+	r := reader{bufio.NewReader(c.in)}
+	res, err := r.readline()
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(res) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+func entries(n int) string {
+	if n == 1 {
+		return "entry"
+	}
+	return "entries"
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestCacheCmd(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	db := r.DB()
+	tests.Check(db.PutWithTTL("expired", []byte("value"), time.Millisecond))
+	tests.Check(db.PutWithTTL("fresh", []byte("value"), time.Hour))
+	tests.Check(db.Put("order_testorder", []byte("{}")))
+	time.Sleep(time.Millisecond * 5)
+
+	c := NewCacheCmd(r, strings.NewReader("n\n")).(*cacheCmd)
+	prompt := new(bytes.Buffer)
+	c.prompt = prompt
+	prune, _, err := c.Cmd().Find([]string{"prune"})
+	tests.Check(err)
+	clear, _, err := c.Cmd().Find([]string{"clear"})
+	tests.Check(err)
+
+	tests.Check(clear.RunE(clear, []string{}))
+	r.Compare(t, "")
+	tests.Compare(t, prompt.String(), "Delete all of the cached data, including the orders in the cart? (y/n) ")
+	prompt.Reset()
+	if !db.Exists("order_testorder") {
+		t.Fatal("should not clear the cache without confirmation")
+	}
+
+	c.in = strings.NewReader("y\n")
+	tests.Check(prune.RunE(prune, []string{}))
+	r.Compare(t, "removed 1 expired entry\n")
+	tests.Compare(t, prompt.String(), "Delete all of the expired cache data? (y/n) ")
+	prompt.Reset()
+	r.ClearBuf()
+	if db.Exists("expired") || !db.Exists("fresh") {
+		t.Error("prune should only delete the expired entries")
+	}
+
+	c.gopts.Yes = true
+	tests.Check(clear.RunE(clear, []string{}))
+	r.Compare(t, "removed 2 entries\n")
+	if prompt.Len() != 0 {
+		t.Errorf("--yes should skip the prompt, got %q", prompt.String())
+	}
+	all, err := db.Map()
+	tests.Check(err)
+	if len(all) != 0 {
+		t.Error("clear should have deleted everything")
+	}
+}
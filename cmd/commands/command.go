@@ -3,9 +3,13 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 
 	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
 	"github.com/spf13/cobra"
 )
 
@@ -46,5 +50,125 @@ note: for zsh you will need to run 'compdef _apizza apizza'`,
 		ValidArgs: validArgs,
 		Aliases:   []string{"comp"},
 	}
+	cmd.AddCommand(newProductCompletionCmd(b))
 	return cmd
 }
+
+func newProductCompletionCmd(b cli.Builder) *cobra.Command {
+	var list bool
+	cmd := &cobra.Command{
+		Use:   "products [bash|zsh]",
+		Short: "Generate completion of product codes for the cart command",
+		Long: `Generate a bash or zsh script that completes product codes for the
+'cart --add' and 'cart new --product' flags. The product codes are read from
+the cached menu so completion works offline and stays up to date whenever
+the menu is updated.
+
+Source it after the main completion script
+    . <(apizza completion bash)
+    . <(apizza completion products bash)`,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		ValidArgs:     []string{"bash", "zsh"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			if list {
+				m, err := data.CachedMenu(b.DB(), b.GlobalOptions().StoreID)
+				if err != nil {
+					return err
+				}
+				printProductCodes(out, m)
+				return nil
+			}
+			if len(args) == 0 {
+				return errors.New("no shell type given; (expected bash or zsh)")
+			}
+			switch args[0] {
+			case "bash":
+				_, err := io.WriteString(out, bashProductCompletion)
+				return err
+			case "zsh":
+				_, err := io.WriteString(out, zshProductCompletion)
+				return err
+			}
+			return errors.New("unknown shell type")
+		},
+	}
+	cmd.Flags().BoolVar(&list, "list", false, "print the product codes and names from the cached menu")
+	cmd.Flags().MarkHidden("list")
+	return cmd
+}
+
+// printProductCodes prints every variant code on the menu followed by a tab
+// and the variant's name.
+func printProductCodes(w io.Writer, m *dawg.Menu) {
+	codes := make([]string, 0, len(m.Variants))
+	for code := range m.Variants {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		fmt.Fprintf(w, "%s\t%s\n", code, m.Variants[code].Name)
+	}
+}
+
+const bashProductCompletion = `# apizza product code completion for bash
+__apizza_product_codes() {
+    apizza completion products --list 2>/dev/null | cut -f1
+}
+
+__apizza_complete_products() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    # bash splits '--add=code' into '--add' '=' 'code'
+    if [[ "$prev" == "=" ]] && [[ $COMP_CWORD -ge 2 ]]; then
+        prev="${COMP_WORDS[COMP_CWORD-2]}"
+    elif [[ "$cur" == "=" ]]; then
+        cur=""
+    fi
+    if [[ " ${COMP_WORDS[*]} " == *" cart "* ]]; then
+        case "$prev" in
+        --add|-a|--product|-p)
+            local prefix=""
+            if [[ "$cur" == *,* ]]; then
+                prefix="${cur%,*},"
+                cur="${cur##*,}"
+            fi
+            COMPREPLY=( $(compgen -P "$prefix" -W "$(__apizza_product_codes)" -- "$cur") )
+            return 0
+            ;;
+        esac
+    fi
+    if declare -F __start_apizza >/dev/null; then
+        __start_apizza "$@"
+    fi
+}
+
+complete -o default -F __apizza_complete_products apizza
+`
+
+const zshProductCompletion = `# apizza product code completion for zsh
+_apizza_products() {
+    local -a codes
+    local line
+    for line in ${(f)"$(apizza completion products --list 2>/dev/null)"}; do
+        codes+=("${line%%$'\t'*}:${${line#*$'\t'}//:/\\:}")
+    done
+    _describe -t products 'product code' codes
+}
+
+_apizza_with_products() {
+    if (( ${words[(I)cart]} )); then
+        case "${words[CURRENT-1]}" in
+        --add|-a|--product|-p)
+            _apizza_products
+            return
+            ;;
+        esac
+    fi
+    if (( $+functions[_apizza] )); then
+        _apizza "$@"
+    fi
+}
+
+compdef _apizza_with_products apizza
+`
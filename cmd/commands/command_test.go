@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strings"
+	"testing"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestProductCompletion(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := newProductCompletionCmd(r)
+	buf := &bytes.Buffer{}
+	c.SetOut(buf)
+
+	tests.Check(c.Flags().Set("list", "true"))
+	if err := c.RunE(c, []string{}); err != data.ErrNoCachedMenu {
+		t.Errorf("expected ErrNoCachedMenu, got %v", err)
+	}
+
+	menu := &dawg.Menu{
+		ID: "4336",
+		Variants: map[string]*dawg.Variant{
+			"14SCREEN": {ItemCommon: dawg.ItemCommon{Code: "14SCREEN", Name: "Large (14\") Hand Tossed Pizza"}},
+			"12SCREEN": {ItemCommon: dawg.ItemCommon{Code: "12SCREEN", Name: "Medium (12\") Hand Tossed Pizza"}},
+		},
+	}
+	raw := &bytes.Buffer{}
+	tests.Check(gob.NewEncoder(raw).Encode(menu))
+	tests.Check(r.DB().Put(data.MenuKey("4336"), raw.Bytes()))
+	tests.Check(r.DB().Put(data.MenuKey("4336")+"_timestamp", []byte("100")))
+	tests.Check(r.DB().Put(data.MenuKey("1111"), []byte("old menu")))
+	tests.Check(r.DB().Put(data.MenuKey("1111")+"_timestamp", []byte("50")))
+	// the saved store's menu is used over a newer menu from another store
+	tests.Check(r.DB().Put(data.MenuKey("2222"), []byte("newer menu")))
+	tests.Check(r.DB().Put(data.MenuKey("2222")+"_timestamp", []byte("200")))
+	tests.Check(data.SaveStore(r.DB(), "4336", cmdtest.TestAddress()))
+
+	tests.Check(c.RunE(c, []string{}))
+	tests.Compare(t, buf.String(), "12SCREEN\tMedium (12\") Hand Tossed Pizza\n14SCREEN\tLarge (14\") Hand Tossed Pizza\n")
+
+	buf.Reset()
+	tests.Check(c.Flags().Set("list", "false"))
+	tests.Check(c.RunE(c, []string{"bash"}))
+	if !strings.Contains(buf.String(), "complete -o default -F __apizza_complete_products apizza") {
+		t.Error("bad bash completion script")
+	}
+	buf.Reset()
+	tests.Check(c.RunE(c, []string{"zsh"}))
+	if !strings.Contains(buf.String(), "compdef _apizza_with_products apizza") {
+		t.Error("bad zsh completion script")
+	}
+	tests.Exp(c.RunE(c, []string{"fish"}))
+	tests.Exp(c.RunE(c, []string{}))
+}
@@ -15,15 +15,20 @@
 package commands
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/harrybrwn/apizza/cmd/cli"
 	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/cmd/opts"
 	"github.com/harrybrwn/apizza/pkg/cache"
 	"github.com/harrybrwn/apizza/pkg/config"
 )
@@ -31,8 +36,9 @@ import (
 type configCmd struct {
 	cli.CliCommand
 	cli.AddressBuilder
-	db   *cache.DataBase
-	conf *cli.Config
+	db    *cache.DataBase
+	conf  *cli.Config
+	gopts *opts.CliFlags
 
 	file   bool
 	dir    bool
@@ -43,6 +49,8 @@ type configCmd struct {
 
 	card string
 	exp  string
+
+	overwrite bool
 }
 
 func (c *configCmd) Run(cmd *cobra.Command, args []string) error {
@@ -86,6 +94,7 @@ func NewConfigCmd(b cli.Builder) cli.CliCommand {
 		AddressBuilder: b,
 		db:             b.DB(),
 		conf:           b.Config(),
+		gopts:          b.GlobalOptions(),
 		file:           false,
 		dir:            false,
 	}
@@ -106,12 +115,179 @@ ex. 'apizza config get name' or 'apizza config set name=<your name>'`
 
 	cmd := c.Cmd()
 	cmd.AddCommand(configSetCmd, configGetCmd)
+	validate := b.Build("validate", "Check the config fields needed to send an order", cli.RunFunction(c.validate))
+	validate.Cmd().Args = cobra.NoArgs
+	export := b.Build("export", "Print the config as json", cli.RunFunction(c.export))
+	export.Cmd().Args = cobra.NoArgs
+	export.Cmd().Long = `Print the config as json so that it can be loaded on another
+machine with 'apizza config import'. Payment information is never exported.`
+	imp := b.Build("import <file>", "Load a config that was saved with 'config export'", cli.RunFunction(c.importConfig))
+	imp.Cmd().Args = cobra.ExactArgs(1)
+	imp.Cmd().Long = `Load a json config file that was saved with 'apizza config export'.
+Every field in the file is validated before anything is changed. Fields that
+are already set are kept unless --overwrite is given and named addresses are
+merged. Payment information is never imported.`
+	imp.Cmd().Flags().BoolVar(&c.overwrite, "overwrite", false, "replace the values that are already set")
+	c.Addcmd(newConfigAddressCmd(b, os.Stdin), validate, export, imp)
 	return c
 }
 
+// ErrInvalidConfig is returned by 'apizza config validate' when one of the
+// required fields is invalid.
+var ErrInvalidConfig = errors.New("config has invalid fields")
+
+func (c *configCmd) validate(cmd *cobra.Command, args []string) error {
+	conf := *c.conf
+	if c.gopts.Service != "" {
+		conf.Service = c.gopts.Service
+	}
+	valid := true
+	for _, check := range conf.Validate() {
+		if check.Err != nil {
+			valid = false
+			c.Printf("%-9s FAIL: %v\n", check.Field+":", check.Err)
+		} else if check.Note != "" {
+			c.Printf("%-9s ok (%s)\n", check.Field+":", check.Note)
+		} else {
+			c.Printf("%-9s ok\n", check.Field+":")
+		}
+	}
+	if !valid {
+		return ErrInvalidConfig
+	}
+	return nil
+}
+
+func (c *configCmd) export(cmd *cobra.Command, args []string) error {
+	conf := *c.conf
+	conf.Card.Number, conf.Card.Expiration = "", ""
+	conf.Payments = nil
+	raw, err := json.MarshalIndent(&conf, "", "  ")
+	if err != nil {
+		return err
+	}
+	c.Printf("%s\n", raw)
+	return nil
+}
+
+func (c *configCmd) importConfig(cmd *cobra.Command, args []string) error {
+	raw, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	imported := &cli.Config{}
+	if err = json.Unmarshal(raw, imported); err != nil {
+		return fmt.Errorf("could not read %s: %v", args[0], err)
+	}
+	if err = c.checkImport(imported); err != nil {
+		return fmt.Errorf("could not import %s: %v", args[0], err)
+	}
+	for _, field := range mergeConfig(c.conf, imported, c.overwrite) {
+		c.Printf("skipped %s, it is already set (use --overwrite to replace it)\n", field)
+	}
+	c.Printf("imported %s\n", args[0])
+	return nil
+}
+
+// checkImport validates every field that is set in an imported config.
+func (c *configCmd) checkImport(conf *cli.Config) error {
+	var problems []string
+	check := func(field string, err error) {
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", field, err))
+		}
+	}
+	if conf.Name != "" {
+		check("name", cli.ValidateName(conf.Name))
+	}
+	if conf.Email != "" {
+		check("email", cli.ValidateEmail(conf.Email))
+	}
+	if conf.Phone != "" {
+		check("phone", cli.ValidatePhone(conf.Phone))
+	}
+	if conf.Service != "" {
+		service, err := cli.ParseService(conf.Service)
+		check("service", err)
+		conf.Service = service
+	}
+	if conf.Address != (obj.Address{}) {
+		check("address", cli.ValidateAddress(&conf.Address))
+	}
+	names := make([]string, 0, len(conf.Addresses))
+	for name := range conf.Addresses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		addr := conf.Addresses[name]
+		check("addresses."+name, cli.ValidateAddress(&addr))
+	}
+	if name := conf.DefaultAddressName; name != "" {
+		_, inFile := conf.NamedAddress(name)
+		_, inConf := c.conf.NamedAddress(name)
+		if !inFile && !inConf && !c.db.WithBucket("addresses").Exists(name) {
+			check("default-address-name", fmt.Errorf("there is no address named '%s'", name))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// mergeConfig copies the fields that are set in src to dst. Fields that are
+// already set in dst are only replaced if overwrite is true, otherwise they
+// are returned as the fields that were skipped. Payment information is never
+// copied.
+func mergeConfig(dst, src *cli.Config, overwrite bool) (skipped []string) {
+	setString := func(field string, d *string, s string) {
+		switch {
+		case s == "" || s == *d:
+		case *d == "" || overwrite:
+			*d = s
+		default:
+			skipped = append(skipped, field)
+		}
+	}
+	setString("name", &dst.Name, src.Name)
+	setString("email", &dst.Email, src.Email)
+	setString("phone", &dst.Phone, src.Phone)
+	setString("default-address-name", &dst.DefaultAddressName, src.DefaultAddressName)
+	setString("service", &dst.Service, src.Service)
+	setString("db-path", &dst.DBPath, src.DBPath)
+
+	setAddress := func(field string, d *obj.Address, s obj.Address) {
+		switch {
+		case s == obj.Address{} || s == *d:
+		case *d == obj.Address{} || overwrite:
+			*d = s
+		default:
+			skipped = append(skipped, field)
+		}
+	}
+	setAddress("address", &dst.Address, src.Address)
+	if len(src.Addresses) > 0 && dst.Addresses == nil {
+		dst.Addresses = make(map[string]obj.Address)
+	}
+	names := make([]string, 0, len(src.Addresses))
+	for name := range src.Addresses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		addr := dst.Addresses[name]
+		setAddress("addresses."+name, &addr, src.Addresses[name])
+		dst.Addresses[name] = addr
+	}
+	return skipped
+}
+
 var configSetCmd = &cobra.Command{
-	Use:   "set",
+	Use:   "set <key> <value>",
 	Short: "change variables in the config file",
+	Long: `Set a config variable with either 'set <key> <value>' or any number of
+'<key>=<value>' pairs. Use dots for nested fields, ex. 'set address.street "1 Main St"'.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return set(args)
 	},
@@ -120,7 +296,7 @@ var configSetCmd = &cobra.Command{
 }
 
 var configGetCmd = &cobra.Command{
-	Use:   "get",
+	Use:   "get <key>...",
 	Short: "print the specified config variable to screen",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return get(args, cmd.OutOrStdout())
@@ -133,24 +309,43 @@ func set(args []string) error {
 	if len(args) < 1 {
 		return errors.New("no variable given")
 	}
+	if len(args) == 2 && !strings.Contains(args[0], "=") {
+		return setKey(args[0], args[1])
+	}
 
 	for _, arg := range args {
-		keys := strings.Split(arg, "=")
+		keys := strings.SplitN(arg, "=", 2)
 		if len(keys) < 2 || keys[0] == "" || keys[1] == "" {
 			return errors.New(`use '<key>=<value>' format (no spaces), use <key>='-' to set as empty`)
 		}
-
-		if keys[1] == "-" {
-			keys[1] = ""
-		}
-		err := config.Set(keys[0], keys[1])
-		if err != nil {
+		if err := setKey(keys[0], keys[1]); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func setKey(key, val string) error {
+	conf := config.Object()
+	if !isKey(conf, key) {
+		return fmt.Errorf("unknown config key '%s', valid keys are:\n  %s",
+			key, strings.Join(config.Keys(conf), "\n  "))
+	}
+	if val == "-" {
+		val = ""
+	}
+	return conf.Set(key, val)
+}
+
+func isKey(conf config.Config, key string) bool {
+	for _, k := range config.Keys(conf) {
+		if config.FieldName(conf, k) == config.FieldName(conf, key) {
+			return true
+		}
+	}
+	return false
+}
+
 func get(args []string, out io.Writer) error {
 	if len(args) < 1 {
 		return errors.New("no variable given")
@@ -3,7 +3,9 @@ package commands
 import (
 	"bytes"
 	"encoding/json"
+	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/harrybrwn/apizza/cmd/cli"
@@ -40,6 +42,9 @@ card:
   number: ""
   expiration: ""
 service: "Carryout"
+addresses: map[]
+payments: map[]
+db-path: ""
 `
 
 func TestConfigStruct(t *testing.T) {
@@ -113,7 +118,10 @@ func TestConfigEdit(t *testing.T) {
         "Number": "",
         "Expiration": ""
     },
-    "Service": "Delivery"
+    "Service": "Delivery",
+    "Addresses": null,
+    "Payments": null,
+    "DBPath": ""
 }`
 	t.Run("edit output", func(t *testing.T) {
 		if os.Getenv("TRAVIS") != "true" {
@@ -202,4 +210,156 @@ func TestConfigSet(t *testing.T) {
 	} else if err.Error() != "use '<key>=<value>' format (no spaces), use <key>='-' to set as empty" {
 		t.Error("wrong error message, got:", err.Error())
 	}
+
+	tests.Check(configSetCmd.RunE(configSetCmd, []string{"address.street", "1 Main St"}))
+	tests.StrEq(conf.Address.Street, "1 Main St", "did not set a nested field")
+	tests.Check(configSetCmd.RunE(configSetCmd, []string{"service", "carryout"}))
+	tests.StrEq(conf.Service, "Carryout", "service should go through Config.Set")
+	for _, key := range []string{"nope", "address.nope", "card", "name.first"} {
+		err := configSetCmd.RunE(configSetCmd, []string{key, "value"})
+		if err == nil {
+			t.Errorf("expected an error for unknown key '%s'", key)
+		} else if !strings.Contains(err.Error(), "valid keys are:") || !strings.Contains(err.Error(), "address.street") {
+			t.Error("unknown key error should list the valid keys, got:", err.Error())
+		}
+	}
+}
+
+func TestConfigAddressCmd(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	r.Conf.Addresses = nil
+	r.Conf.DefaultAddressName = ""
+
+	in := strings.NewReader("1600 Pennsylvania Ave NW\nWashington\nDC\n20500\n")
+	c := newConfigAddressCmd(r, in)
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	r.Compare(t, "No addresses in config (see 'apizza config address add')\n")
+	r.ClearBuf()
+
+	add, _, err := c.Cmd().Find([]string{"add"})
+	tests.Check(err)
+	tests.Check(add.RunE(add, []string{"work"}))
+	r.ClearBuf()
+	addr, ok := r.Conf.NamedAddress("work")
+	if !ok {
+		t.Fatal("should have added an address named 'work'")
+	}
+	tests.StrEq(addr.Zipcode, "20500", "wrong zipcode")
+	tests.StrEq(r.Conf.DefaultAddressName, "work", "first address should be the default")
+
+	r.Conf.Addresses["home"] = *cmdtest.TestAddress()
+	use, _, err := c.Cmd().Find([]string{"use"})
+	tests.Check(err)
+	tests.Check(use.RunE(use, []string{"home"}))
+	tests.StrEq(r.Conf.DefaultAddressName, "home", "did not set the default address")
+	tests.Exp(use.RunE(use, []string{"nowhere"}))
+
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	r.Compare(t, `home (default):
+  1600 Pennsylvania Ave NW
+  Washington, DC 20500
+work:
+  1600 Pennsylvania Ave NW
+  Washington, DC 20500
+`)
+}
+
+func TestConfigValidateCmd(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	r.Conf.Name = "Joe Blow"
+	r.Conf.Email = "joe@blow.com"
+	r.Conf.Phone = "(123) 123-1234"
+	r.Conf.Address = *cmdtest.TestAddress()
+	r.Conf.DefaultAddressName = ""
+
+	c := NewConfigCmd(r)
+	validate, _, err := c.Cmd().Find([]string{"validate"})
+	tests.Check(err)
+	tests.Check(validate.RunE(validate, []string{}))
+	r.Compare(t, `name:     ok
+email:    ok
+phone:    ok
+address:  ok
+`)
+	r.ClearBuf()
+
+	r.Conf.Name = "Joe"
+	r.Conf.Email = "joe.blow.com"
+	r.Conf.Address.Zipcode = "205"
+	if err = validate.RunE(validate, []string{}); err != ErrInvalidConfig {
+		t.Errorf("expected ErrInvalidConfig, got %v", err)
+	}
+	r.Compare(t, `name:     FAIL: must have a first and last name
+email:    FAIL: 'joe.blow.com' is not a valid email address
+phone:    ok
+address:  FAIL: bad zipcode '205'
+`)
+}
+
+func TestConfigExportImport(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	r.Conf.Name = "Joe Blow"
+	r.Conf.Address = *cmdtest.TestAddress()
+	r.Conf.Card.Number = "4100123422343234"
+	r.Conf.Payments = map[string]cli.Payment{"work": {LastFour: "1234", Ref: "card-id"}}
+
+	c := NewConfigCmd(r)
+	export, _, err := c.Cmd().Find([]string{"export"})
+	tests.Check(err)
+	imp, _, err := c.Cmd().Find([]string{"import"})
+	tests.Check(err)
+
+	tests.Check(export.RunE(export, []string{}))
+	if r.Contains("4100123422343234") || r.Contains("card-id") {
+		t.Error("payment information should not be exported")
+	}
+	exported := cli.Config{}
+	tests.Fatal(json.Unmarshal(r.Out.Bytes(), &exported))
+	tests.StrEq(exported.Name, "Joe Blow", "wrong exported name")
+	tests.StrEq(r.Conf.Card.Number, "4100123422343234", "export should not change the config")
+	r.ClearBuf()
+
+	file := tests.NamedTempFile("apizza", "config.json")
+	defer os.Remove(file)
+	writeFile := func(data string) {
+		tests.Fatal(ioutil.WriteFile(file, []byte(data), 0644))
+	}
+
+	writeFile(`{"name":"Joe", "email":"joe.blow.com", "addresses":{"work":{"street":"1 Main St"}}}`)
+	err = imp.RunE(imp, []string{file})
+	tests.Exp(err)
+	exp := "could not import " + file + ": name: must have a first and last name, email: 'joe.blow.com' is not a valid email address, " +
+		"addresses.work: no city, bad state code '', bad zipcode ''"
+	tests.StrEq(err.Error(), exp, "wrong error: %v", err)
+	tests.StrEq(r.Conf.Email, "", "a bad import should not change the config")
+
+	writeFile(`{
+		"name":"Jane Blow", "email":"jane@blow.com", "service":"carryout",
+		"card":{"number":"4100123422343234"},
+		"addresses":{"work":{"street":"1 Main St","cityName":"Springfield","state":"IL","zipcode":"62701"}},
+		"default-address-name":"work"
+	}`)
+	r.Conf.Service = ""
+	tests.Check(imp.RunE(imp, []string{file}))
+	r.Compare(t, "skipped name, it is already set (use --overwrite to replace it)\nimported "+file+"\n")
+	tests.StrEq(r.Conf.Name, "Joe Blow", "name should not be replaced without --overwrite")
+	tests.StrEq(r.Conf.Email, "jane@blow.com", "empty fields should be imported")
+	tests.StrEq(r.Conf.Service, "Carryout", "the service should be normalized")
+	tests.StrEq(r.Conf.DefaultAddressName, "work", "wrong default address name")
+	tests.StrEq(r.Conf.Card.Number, "4100123422343234", "wrong card number")
+	if _, ok := r.Conf.NamedAddress("work"); !ok {
+		t.Error("named addresses should be imported")
+	}
+	r.ClearBuf()
+
+	tests.Check(imp.Flags().Set("overwrite", "true"))
+	tests.Check(imp.RunE(imp, []string{file}))
+	r.Compare(t, "imported "+file+"\n")
+	tests.StrEq(r.Conf.Name, "Jane Blow", "name should be replaced with --overwrite")
 }
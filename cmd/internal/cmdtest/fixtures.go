@@ -0,0 +1,140 @@
+package cmdtest
+
+// StoreID is the id of the store served by Server.
+const StoreID = "4336"
+
+// These are the canned responses sent by Server. They are trimmed down
+// versions of real responses from dominos.
+var (
+	// StoreLocatorJSON is the response for /power/store-locator.
+	StoreLocatorJSON = `{
+	"Status": 0,
+	"Granularity": "Exact",
+	"Address": {"Street": "1600 PENNSYLVANIA AVE NW", "City": "WASHINGTON", "Region": "DC", "PostalCode": "20500"},
+	"Stores": [
+		{
+			"StoreID": "4336",
+			"IsOnlineNow": true,
+			"IsOpen": true,
+			"IsDeliveryStore": true,
+			"Phone": "202-555-0100",
+			"AddressDescription": "1300 L St NW\nWashington, DC 20005",
+			"MinDistance": 0.7,
+			"MaxDistance": 0.7
+		}
+	]
+}`
+
+	// StoreProfileJSON is the response for /power/store/4336/profile. The
+	// store is open all the time so that the store hours never stop a test.
+	StoreProfileJSON = `{
+	"Status": 0,
+	"StoreID": "4336",
+	"IsOpen": true,
+	"IsOnlineNow": true,
+	"IsDeliveryStore": true,
+	"Phone": "202-555-0100",
+	"AcceptablePaymentTypes": ["Cash", "CreditCard"],
+	"AcceptableCreditCards": ["American Express", "Discover Card", "Mastercard", "Visa"],
+	"AddressDescription": "1300 L St NW\nWashington, DC 20005",
+	"PostalCode": "20005",
+	"City": "Washington",
+	"StreetName": "1300 L St NW",
+	"StoreCoordinates": {"StoreLatitude": "38.9036", "StoreLongitude": "-77.0304"},
+	"ServiceIsOpen": {"Carryout": true, "Delivery": true},
+	"ServiceMethodEstimatedWaitMinutes": {"Carryout": {"Min": 10, "Max": 15}, "Delivery": {"Min": 20, "Max": 30}},
+	"AllowCarryoutOrders": true,
+	"AllowDeliveryOrders": true,
+	"Hours": {` + allDay + `},
+	"ServiceHours": {"Carryout": {` + allDay + `}, "Delivery": {` + allDay + `}},
+	"MinimumDeliveryOrderAmount": 0,
+	"TimeZoneMinutes": -240,
+	"TimeZoneCode": "GMT-04:00",
+	"FutureOrderDelayInHours": 1
+}`
+
+	// MenuJSON is the response for /power/store/4336/menu.
+	MenuJSON = `{
+	"Status": 0,
+	"ID": "4336",
+	"Categorization": {
+		"Food": {
+			"Code": "Food", "Name": "Food",
+			"Categories": [
+				{"Code": "Pizza", "Name": "Pizza", "Products": ["S_PIZZA"]},
+				{"Code": "Drinks", "Name": "Drinks", "Products": ["F_COKE"]}
+			]
+		},
+		"Coupons": {"Code": "Coupons", "Name": "Coupons"},
+		"PreconfiguredProducts": {"Code": "PreconfiguredProducts", "Name": "Popular Items"}
+	},
+	"Products": {
+		"S_PIZZA": {
+			"Code": "S_PIZZA",
+			"Name": "Hand Tossed",
+			"Description": "Garlic-seasoned crust with a rich, buttery taste.",
+			"ProductType": "Pizza",
+			"Variants": ["10SCREEN", "12SCREEN", "14SCREEN"],
+			"AvailableToppings": "X=0:0.5:1:1.5,C=0:0.5:1:1.5:2,P=1/1,M=1/1",
+			"DefaultToppings": "X=1,C=1",
+			"Tags": {"OptionQtys": ["0", "0.5", "1", "1.5", "2"]}
+		},
+		"F_COKE": {
+			"Code": "F_COKE",
+			"Name": "Coke",
+			"Description": "The authentic cola.",
+			"ProductType": "Drinks",
+			"Variants": ["2LCOKE"]
+		}
+	},
+	"Variants": {
+		"10SCREEN": {"Code": "10SCREEN", "Name": "Small (10\") Hand Tossed Pizza", "Price": "9.99", "ProductCode": "S_PIZZA", "Tags": {"DefaultToppings": "X=1,C=1"}},
+		"12SCREEN": {"Code": "12SCREEN", "Name": "Medium (12\") Hand Tossed Pizza", "Price": "11.99", "ProductCode": "S_PIZZA", "Tags": {"DefaultToppings": "X=1,C=1"}},
+		"14SCREEN": {"Code": "14SCREEN", "Name": "Large (14\") Hand Tossed Pizza", "Price": "13.99", "ProductCode": "S_PIZZA", "Tags": {"DefaultToppings": "X=1,C=1"}},
+		"2LCOKE": {"Code": "2LCOKE", "Name": "2-Liter Coke", "Price": "3.19", "ProductCode": "F_COKE"}
+	},
+	"Toppings": {
+		"Pizza": {
+			"X": {"Code": "X", "Name": "Robust Inspired Tomato Sauce"},
+			"C": {"Code": "C", "Name": "Cheese"},
+			"P": {"Code": "P", "Name": "Pepperoni"},
+			"M": {"Code": "M", "Name": "Mushrooms"}
+		}
+	},
+	"PreconfiguredProducts": {},
+	"Sides": {}
+}`
+
+	// ValidateOrderJSON is the response for /power/validate-order.
+	ValidateOrderJSON = `{"Status": 0, "Order": {"Status": 0, "OrderID": "mock-order-id", "StatusItems": []}}`
+
+	// PriceOrderJSON is the response for /power/price-order.
+	PriceOrderJSON = `{
+	"Status": 0,
+	"Order": {
+		"Status": 0,
+		"OrderID": "mock-order-id",
+		"Amounts": {"Menu": 13.99, "Tax": 0.84, "Customer": 14.83, "Payment": 14.83},
+		"AmountsBreakdown": {"FoodAndBeverage": "13.99", "Tax": 0.84, "Customer": 14.83},
+		"PulseOrderGuid": "mock-pulse-guid"
+	}
+}`
+
+	// PlaceOrderJSON is the response for /power/place-order.
+	PlaceOrderJSON = `{
+	"Status": 0,
+	"Order": {
+		"Status": 0,
+		"OrderID": "mock-order-id",
+		"StoreID": "4336",
+		"StoreOrderID": "2020-01-01#123",
+		"Amounts": {"Menu": 13.99, "Tax": 0.84, "Customer": 14.83, "Payment": 14.83},
+		"EstimatedWaitMinutes": "16-26"
+	}
+}`
+)
+
+const day = `[{"OpenTime": "00:00", "CloseTime": "00:00"}]`
+
+const allDay = `"Sun": ` + day + `, "Mon": ` + day + `, "Tue": ` + day + `, "Wed": ` + day +
+	`, "Thu": ` + day + `, "Fri": ` + day + `, "Sat": ` + day
@@ -2,6 +2,7 @@ package cmdtest
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"io/ioutil"
@@ -25,6 +26,14 @@ type Recorder struct {
 	Out        *bytes.Buffer
 	cfgHasFile bool
 	addr       dawg.Address
+
+	// Geo is the geocoder given by Geocoder, the default
+	// is used if it is nil.
+	Geo dawg.Geocoder
+
+	// ColorOutput is returned by Color, the output
+	// is not colored by default.
+	ColorOutput bool
 }
 
 var services = []string{dawg.Carryout, dawg.Delivery}
@@ -44,7 +53,7 @@ func NewRecorder() *Recorder {
 	conf.Address = *addr
 
 	return &Recorder{
-		DataBase:   TempDB(),
+		DataBase:   cache.NewMemoryDB(),
 		Out:        new(bytes.Buffer),
 		Conf:       conf,
 		addr:       addr,
@@ -84,6 +93,34 @@ func (r *Recorder) GlobalOptions() *opts.CliFlags {
 	return &opts.CliFlags{}
 }
 
+// Context returns the background context.
+func (r *Recorder) Context() context.Context {
+	return context.Background()
+}
+
+// Logger returns a logger that writes nothing.
+func (r *Recorder) Logger() *cli.Logger {
+	return cli.NewLogger(ioutil.Discard, 0)
+}
+
+// Progress returns a progress spinner that is never shown.
+func (r *Recorder) Progress() *cli.Progress {
+	return cli.NewProgress(ioutil.Discard, false)
+}
+
+// Geocoder returns the recorder's geocoder.
+func (r *Recorder) Geocoder() dawg.Geocoder {
+	if r.Geo == nil {
+		return dawg.DominosGeocoder{}
+	}
+	return r.Geo
+}
+
+// Color returns true if the output should be colored.
+func (r *Recorder) Color() bool {
+	return r.ColorOutput
+}
+
 // ToApp returns the arguments needed to create a cmd.App.
 func (r *Recorder) ToApp() (*cache.DataBase, *cli.Config, io.Writer) {
 	return r.DB(), r.Conf, r.Output()
@@ -138,10 +175,9 @@ func (r *Recorder) ClearBuf() {
 
 // FreshDB will close the old database, delete it, and open a fresh one.
 func (r *Recorder) FreshDB() error {
-	var err2 error
-	err1 := r.DataBase.Destroy()
-	r.DataBase, err2 = cache.GetDB(tests.NamedTempFile("test", "apizza_test.db"))
-	return errs.Pair(err1, err2)
+	err := r.DataBase.Destroy()
+	r.DataBase = cache.NewMemoryDB()
+	return err
 }
 
 // Contains will return true if s is contained within the output buffer
@@ -0,0 +1,125 @@
+package cmdtest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/harrybrwn/apizza/dawg"
+)
+
+// Server is a fake dominos server. It serves the store locator, store
+// profile, menu, validate-order, price-order, and place-order endpoints with
+// the canned responses in this package so that commands can be tested
+// without sending requests to dominos.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	requests  []string
+	bodies    map[string][]byte
+	responses map[string]response
+	handlers  map[string]http.HandlerFunc
+}
+
+type response struct {
+	status int
+	body   string
+}
+
+// NewServer starts a fake dominos server and sends all the requests from the
+// dawg package to it. Close must be called to stop the server and to go back
+// to sending requests to dominos.
+func NewServer() *Server {
+	s := &Server{
+		bodies:   make(map[string][]byte),
+		handlers: make(map[string]http.HandlerFunc),
+		responses: map[string]response{
+			"/power/store-locator":                          {http.StatusOK, StoreLocatorJSON},
+			fmt.Sprintf("/power/store/%s/profile", StoreID): {http.StatusOK, StoreProfileJSON},
+			fmt.Sprintf("/power/store/%s/menu", StoreID):    {http.StatusOK, MenuJSON},
+			"/power/validate-order":                         {http.StatusOK, ValidateOrderJSON},
+			"/power/price-order":                            {http.StatusOK, PriceOrderJSON},
+			"/power/place-order":                            {http.StatusOK, PlaceOrderJSON},
+		},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	if err := dawg.SetBaseURL(s.URL); err != nil {
+		s.Server.Close()
+		panic(err)
+	}
+	return s
+}
+
+// Close stops the server and sends requests to dominos again.
+func (s *Server) Close() {
+	s.Server.Close()
+	dawg.SetBaseURL("")
+}
+
+// SetResponse changes the response sent for a path.
+func (s *Server) SetResponse(path string, status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[path] = response{status: status, body: body}
+}
+
+// HandleFunc uses a handler for a path instead of a canned response. The
+// request body can still be read by the handler.
+func (s *Server) HandleFunc(path string, fn http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[path] = fn
+}
+
+// Requests returns the paths of every request the server has gotten.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// Count returns the number of requests sent to a path.
+func (s *Server) Count(path string) (n int) {
+	for _, p := range s.Requests() {
+		if p == path {
+			n++
+		}
+	}
+	return n
+}
+
+// Body returns the body of the last request sent to a path.
+func (s *Server) Body(path string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bodies[path]
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.Path)
+	s.bodies[r.URL.Path] = body
+	resp, ok := s.responses[r.URL.Path]
+	handler := s.handlers[r.URL.Path]
+	s.mu.Unlock()
+	if handler != nil {
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		handler(w, r)
+		return
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if strings.HasPrefix(resp.body, "{") {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+	w.WriteHeader(resp.status)
+	w.Write([]byte(resp.body))
+}
@@ -17,7 +17,8 @@ func TestAddress() *obj.Address {
 	}
 }
 
-// TempDB will create a new database in the temp folder.
+// TempDB will create a new database in the temp folder. Use
+// cache.NewMemoryDB for tests that do not need a database on disk.
 func TempDB() *cache.DataBase {
 	db, err := cache.GetDB(tests.NamedTempFile("cmdtest", "apizza_tmp.db"))
 	if err != nil {
@@ -0,0 +1,156 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+// HistoryBucket is the name of the database bucket that stores the
+// order history.
+const HistoryBucket = "history"
+
+// HistoryEntry is a record of an order that has been sent to dominos.
+type HistoryEntry struct {
+	Name          string               `json:"name"`
+	Time          time.Time            `json:"time"`
+	StoreID       string               `json:"store_id"`
+	ServiceMethod string               `json:"service_method"`
+	Products      []*dawg.OrderProduct `json:"products"`
+	// Total is the price of the order without the tip.
+	Total    float64 `json:"total"`
+	Tip      float64 `json:"tip,omitempty"`
+	Estimate string  `json:"estimate,omitempty"`
+	// Scheduled is the time that a scheduled order was placed for.
+	Scheduled *time.Time `json:"scheduled,omitempty"`
+}
+
+// NewHistoryEntry creates a history entry from an order.
+func NewHistoryEntry(o *dawg.Order) *HistoryEntry {
+	total, err := o.Price()
+	if err != nil {
+		total = 0
+	}
+	e := &HistoryEntry{
+		Name:          o.Name(),
+		Time:          time.Now(),
+		StoreID:       o.StoreID,
+		ServiceMethod: o.ServiceMethod,
+		Products:      o.Products,
+		Total:         total,
+		Tip:           o.Tip,
+	}
+	if t, ok := o.ScheduledTime(); ok {
+		e.Scheduled = &t
+	}
+	return e
+}
+
+// Entry returns the nth most recent history entry where one
+// is the newest order.
+func Entry(db *cache.DataBase, n int) (*HistoryEntry, error) {
+	if n < 1 {
+		return nil, errors.New("history entries start at 1")
+	}
+	entries, err := History(db, n)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("there are no orders in the history")
+	} else if len(entries) < n {
+		return nil, fmt.Errorf("there are only %d orders in the history", len(entries))
+	}
+	return entries[n-1], nil
+}
+
+// AddToHistory will append a completed order to the order history.
+func AddToHistory(db *cache.DataBase, o *dawg.Order) error {
+	return SaveHistoryEntry(db, NewHistoryEntry(o))
+}
+
+// SaveHistoryEntry will store a history entry keyed by its timestamp.
+func SaveHistoryEntry(db *cache.DataBase, e *HistoryEntry) error {
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return db.WithBucket(HistoryBucket).Put(historyKey(e.Time), raw)
+}
+
+// History returns the most recent entries in the order history with the
+// newest order first. A limit less than one will return the full history.
+func History(db *cache.DataBase, limit int) ([]*HistoryEntry, error) {
+	all, err := db.WithBucket(HistoryBucket).Map()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(all))
+	for k := range all {
+		keys = append(keys, k)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	if limit > 0 && len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	entries := make([]*HistoryEntry, len(keys))
+	for i, k := range keys {
+		entries[i] = new(HistoryEntry)
+		if err = json.Unmarshal(all[k], entries[i]); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// PrintHistory will print a list of history entries.
+func PrintHistory(w io.Writer, entries []*HistoryEntry) error {
+	if len(entries) == 0 {
+		_, err := fmt.Fprintln(w, "No order history.")
+		return err
+	}
+	for i, e := range entries {
+		fmt.Fprintf(w, "%d. %s %s\n", i+1, e.Name, e.Time.Format(time.RFC1123))
+		fmt.Fprintf(w, "  store:  %s\n", e.StoreID)
+		fmt.Fprintf(w, "  method: %s\n", e.ServiceMethod)
+		fmt.Fprintln(w, "  products:")
+		for _, p := range e.Products {
+			fmt.Fprintf(w, "    %s x%d\n", p.Code, p.Qty)
+		}
+		if e.Scheduled != nil {
+			fmt.Fprintf(w, "  scheduled: %s\n", e.Scheduled.Format(ScheduleFormat))
+		} else if e.Estimate != "" {
+			fmt.Fprintf(w, "  wait:   %s\n", FormatEstimate(e.Estimate))
+		}
+		if e.Tip > 0 {
+			fmt.Fprintf(w, "  tip:    $%.2f\n", e.Tip)
+		}
+		if _, err := fmt.Fprintf(w, "  total:  $%.2f\n", e.Total+e.Tip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ScheduleFormat is the layout used to show the time of a scheduled order.
+const ScheduleFormat = "Monday, Jan 2 at 3:04pm"
+
+// FormatEstimate formats the wait estimate from an order confirmation.
+func FormatEstimate(wait string) string {
+	if wait == "" {
+		return "estimate unavailable"
+	}
+	return wait + " minutes"
+}
+
+// history keys are zero padded so that they sort chronologically.
+func historyKey(t time.Time) string {
+	return fmt.Sprintf("%020d", t.UnixNano())
+}
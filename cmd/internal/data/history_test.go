@@ -0,0 +1,100 @@
+package data
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestHistory(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer func() { tests.Check(db.Destroy()) }()
+	buf := new(bytes.Buffer)
+
+	entries, err := History(db, 0)
+	tests.Check(err)
+	tests.Check(PrintHistory(buf, entries))
+	tests.Compare(t, buf.String(), "No order history.\n")
+	buf.Reset()
+	_, err = Entry(db, 1)
+	tests.Exp(err, "should not find an entry in an empty history")
+
+	start := time.Date(2020, time.May, 1, 12, 0, 0, 0, time.UTC)
+	for i, name := range []string{"first", "second", "third"} {
+		tests.Check(SaveHistoryEntry(db, &HistoryEntry{
+			Name:          name,
+			Time:          start.Add(time.Duration(i) * time.Hour),
+			StoreID:       "4336",
+			ServiceMethod: dawg.Carryout,
+			Products: []*dawg.OrderProduct{
+				{ItemCommon: dawg.ItemCommon{Code: "12SCREEN"}, Qty: i + 1},
+			},
+			Total: 10.5,
+		}))
+	}
+
+	entries, err = History(db, 2)
+	tests.Check(err)
+	if len(entries) != 2 {
+		t.Fatal("wrong number of history entries")
+	}
+	tests.StrEq(entries[0].Name, "third", "newest entry should be first")
+	tests.StrEq(entries[1].Name, "second", "wrong history order")
+
+	tests.Check(PrintHistory(buf, entries[1:]))
+	tests.Compare(t, buf.String(), `1. second Fri, 01 May 2020 13:00:00 UTC
+  store:  4336
+  method: Carryout
+  products:
+    12SCREEN x2
+  total:  $10.50
+`)
+
+	entries, err = History(db, 0)
+	tests.Check(err)
+	if len(entries) != 3 {
+		t.Error("a limit of zero should give the full history")
+	}
+	buf.Reset()
+	entries[0].Estimate = "16-26"
+	tests.Check(PrintHistory(buf, entries[:1]))
+	if !bytes.Contains(buf.Bytes(), []byte("  wait:   16-26 minutes\n")) {
+		t.Errorf("history should show the wait estimate, got %q", buf.String())
+	}
+	buf.Reset()
+	entries[0].Tip = 2.5
+	tests.Check(PrintHistory(buf, entries[:1]))
+	if !bytes.Contains(buf.Bytes(), []byte("  tip:    $2.50\n  total:  $13.00\n")) {
+		t.Errorf("history should show the tip, got %q", buf.String())
+	}
+	tests.StrEq(FormatEstimate(""), "estimate unavailable", "wrong missing estimate")
+
+	buf.Reset()
+	later := start.Add(30 * time.Hour)
+	tests.Check(SaveHistoryEntry(db, &HistoryEntry{Name: "later", Time: start.Add(4 * time.Hour), Scheduled: &later, Estimate: "16-26"}))
+	e, err := Entry(db, 1)
+	tests.Check(err)
+	if e.Scheduled == nil || !e.Scheduled.Equal(later) {
+		t.Fatal("the scheduled time should be saved in the history")
+	}
+	tests.Check(PrintHistory(buf, []*HistoryEntry{e}))
+	if !bytes.Contains(buf.Bytes(), []byte("  scheduled: Saturday, May 2 at 6:00pm\n")) ||
+		bytes.Contains(buf.Bytes(), []byte("wait:")) {
+		t.Errorf("history should show the scheduled time instead of the wait, got %q", buf.String())
+	}
+	tests.Check(db.WithBucket(HistoryBucket).Delete(historyKey(e.Time)))
+
+	e, err = Entry(db, 2)
+	tests.Check(err)
+	tests.StrEq(e.Name, "second", "wrong entry")
+	if _, err = Entry(db, 4); err == nil || err.Error() != "there are only 3 orders in the history" {
+		t.Error("wrong error for an entry past the end of the history, got:", err)
+	}
+	_, err = Entry(db, 0)
+	tests.Exp(err, "history entries should start at one")
+}
@@ -19,8 +19,13 @@ const OrderPrefix = "user_order_"
 
 // OpenDatabase make the default database.
 func OpenDatabase() (*cache.DataBase, error) {
-	dbPath := filepath.Join(config.Folder(), "cache", "apizza.db")
-	return cache.GetDB(dbPath)
+	return cache.GetDB(DefaultDBPath())
+}
+
+// DefaultDBPath is the path of the database when one is not
+// given in the config or with the --db flag.
+func DefaultDBPath() string {
+	return filepath.Join(config.Folder(), "cache", "apizza.db")
 }
 
 // ListOrders will return a list of orders stored in the database.
@@ -100,7 +105,10 @@ func GetOrder(name string, db cache.Getter) (*dawg.Order, error) {
 // Also sends the order to the validation endpoint after saving it to the
 // cache.Putter.
 func SaveOrder(o *dawg.Order, w io.Writer, db cache.Putter) error {
-	raw, err := json.Marshal(o)
+	// payment information is never saved with the order
+	saved := *o
+	saved.Payments = nil
+	raw, err := json.Marshal(&saved)
 	if err != nil {
 		return err
 	}
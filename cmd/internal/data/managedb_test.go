@@ -2,7 +2,9 @@ package data
 
 import (
 	"bytes"
+	"context"
 	"log"
+	"strconv"
 	"testing"
 	"time"
 
@@ -82,7 +84,7 @@ func TestMenuCacherJSON(t *testing.T) {
 	db := cmdtest.TempDB()
 	defer db.Destroy()
 
-	cacher := NewMenuCacher(time.Second, db, func() *dawg.Store { return testStore })
+	cacher := NewMenuCacher(context.Background, time.Second, db, func() *dawg.Store { return testStore })
 	var buf bytes.Buffer
 	log.SetFlags(0)
 	log.SetOutput(&buf)
@@ -95,14 +97,14 @@ func TestMenuCacherJSON(t *testing.T) {
 		t.Error("cacher should not have a menu yet")
 	}
 
-	tests.Check(db.UpdateTS("menu", cacher))
+	tests.Check(db.UpdateTS(cacher.CacheKey(), cacher))
 	if c.m == nil {
 		t.Error("cacher should have a menu now")
 	}
 	if cacher.Menu() == nil {
 		t.Error("cacher should have a menu now")
 	}
-	data, err := db.Get("menu")
+	data, err := db.Get(MenuKey(testStore.ID))
 	tests.Check(err)
 	if len(data) == 0 {
 		t.Error("should have stored a menu")
@@ -113,7 +115,92 @@ func TestMenuCacherJSON(t *testing.T) {
 	}
 	buf.Reset()
 
-	tests.Check(db.UpdateTS("menu", cacher))
+	tests.Check(db.UpdateTS(cacher.CacheKey(), cacher))
 	c.m = nil
-	tests.Check(db.UpdateTS("menu", c))
+	tests.Check(db.UpdateTS(c.CacheKey(), c))
+}
+
+func TestDeleteMenus(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer db.Destroy()
+	for _, key := range []string{"menu", MenuKey("4336"), MenuKey("4336") + "_timestamp", MenuKey("4339"), OrderPrefix + "menu"} {
+		tests.Check(db.Put(key, []byte("x")))
+	}
+	c := NewMenuCacher(context.Background, time.Hour, db, func() *dawg.Store {
+		return &dawg.Store{ID: "4339"}
+	})
+	tests.StrEq(c.CacheKey(), "menu_4339", "wrong menu cache key")
+
+	tests.Check(DeleteMenus(db))
+	all, err := db.Map()
+	tests.Check(err)
+	if len(all) != 1 {
+		t.Errorf("expected only one key left, got %d", len(all))
+	}
+	if _, ok := all[OrderPrefix+"menu"]; !ok {
+		t.Error("should not have deleted an order")
+	}
+}
+
+func TestCachedMenu(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer func() { tests.Check(db.Destroy()) }()
+
+	_, err := CachedMenu(db, "")
+	if err != ErrNoCachedMenu {
+		t.Errorf("expected ErrNoCachedMenu, got %v", err)
+	}
+	tests.Check(SaveMenu(db, "4336", &dawg.Menu{ID: "saved"}))
+	tests.Check(SaveMenu(db, "4339", &dawg.Menu{ID: "newest"}))
+	tests.Check(db.Put(MenuKey("4339")+"_timestamp", []byte(strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))))
+
+	m, err := CachedMenu(db, "")
+	tests.Check(err)
+	tests.StrEq(m.ID, "newest", "the newest menu should be used without a saved store")
+
+	tests.Check(SaveStore(db, "4336", cmdtest.TestAddress()))
+	m, err = CachedMenu(db, "")
+	tests.Check(err)
+	tests.StrEq(m.ID, "saved", "the saved store's menu should be used")
+	m, err = CachedMenu(db, "4339")
+	tests.Check(err)
+	tests.StrEq(m.ID, "newest", "an explicit store id should be used")
+
+	tests.Check(SaveStore(db, "1234", cmdtest.TestAddress()))
+	m, err = CachedMenu(db, "")
+	tests.Check(err)
+	tests.StrEq(m.ID, "newest", "the newest menu should be used when the saved store has no menu")
+}
+
+func TestSavedStore(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer func() { tests.Check(db.Destroy()) }()
+	addr := cmdtest.TestAddress()
+
+	id, err := StoreFor(db, addr)
+	tests.Check(err)
+	tests.StrEq(id, "", "there should be no saved store")
+
+	tests.Check(SaveStore(db, "4336", addr))
+	id, saved, err := SavedStore(db)
+	tests.Check(err)
+	tests.StrEq(id, "4336", "wrong store id")
+	if *saved != *addr {
+		t.Errorf("wrong address saved: %+v", saved)
+	}
+	id, err = StoreFor(db, addr)
+	tests.Check(err)
+	tests.StrEq(id, "4336", "the store should be used for the same address")
+
+	moved := *addr
+	moved.Street = "1 Main St"
+	id, err = StoreFor(db, &moved)
+	tests.Check(err)
+	tests.StrEq(id, "", "the store should not be used for a different address")
+	if db.Exists(StoreIDKey) || db.Exists(StoreAddressKey) {
+		t.Error("the saved store should be removed when the address changes")
+	}
 }
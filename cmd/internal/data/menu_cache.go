@@ -2,12 +2,17 @@ package data
 
 import (
 	"bytes"
+	"context"
 	"encoding/gob"
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/harrybrwn/apizza/cmd/cli"
 	"github.com/harrybrwn/apizza/dawg"
 	"github.com/harrybrwn/apizza/pkg/cache"
 	"github.com/harrybrwn/apizza/pkg/errs"
@@ -18,16 +23,115 @@ import (
 type MenuCacher interface {
 	cache.Updater
 	Menu() *dawg.Menu
+
+	// CacheKey is the database key that the current store's menu
+	// is cached under. It should also be used for the menu timestamp.
+	CacheKey() string
+}
+
+// MenuPrefix is the prefix for all database keys used to cache menus.
+const MenuPrefix = "menu_"
+
+// legacyMenuKey is the key that was used to cache one menu for all stores.
+const legacyMenuKey = "menu"
+
+// MenuKey returns the database key used to cache the menu of a store.
+func MenuKey(storeID string) string {
+	return MenuPrefix + storeID
+}
+
+// DeleteMenus removes every cached menu from the database, along with
+// the menu that was cached before menus were cached per store.
+func DeleteMenus(db *cache.DataBase) error {
+	all, err := db.Map()
+	if err != nil {
+		return err
+	}
+	for key := range all {
+		if key == legacyMenuKey || strings.HasPrefix(key, MenuPrefix) {
+			if err = db.Delete(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // NewMenuCacher creates a new MenuCacher.
 func NewMenuCacher(
+	ctx func() context.Context,
 	decay time.Duration,
 	db cache.Storage,
 	store func() *dawg.Store,
 ) MenuCacher {
 	// use gob to cache the menu in binary format
-	return NewGobMenuCacher(decay, db, store)
+	return NewGobMenuCacher(ctx, decay, db, store)
+}
+
+// ErrNoCachedMenu is returned when there is no menu stored in the database.
+var ErrNoCachedMenu = errors.New("no cached menu (run 'apizza menu' to cache one)")
+
+// CachedMenu reads a menu that was cached by a gob MenuCacher without sending
+// any requests to dominos. If storeID is empty, the menu of the saved store
+// is used, or the most recently cached menu if that store has none.
+func CachedMenu(db *cache.DataBase, storeID string) (*dawg.Menu, error) {
+	if storeID == "" {
+		id, _, err := SavedStore(db)
+		if err != nil {
+			return nil, err
+		}
+		if id != "" && db.Exists(MenuKey(id)) {
+			storeID = id
+		}
+	}
+	key := MenuKey(storeID)
+	if storeID == "" {
+		all, err := db.Map()
+		if err != nil {
+			return nil, err
+		}
+		key = newestMenuKey(all)
+	}
+	raw, err := db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, ErrNoCachedMenu
+	}
+	m := &dawg.Menu{}
+	return m, gob.NewDecoder(bytes.NewReader(raw)).Decode(m)
+}
+
+// SaveMenu caches a store's menu and resets the menu timestamp so that
+// it is used like a freshly downloaded menu.
+func SaveMenu(db *cache.DataBase, storeID string, m *dawg.Menu) error {
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(m); err != nil {
+		return err
+	}
+	key := MenuKey(storeID)
+	return errs.Pair(db.Put(key, buf.Bytes()), db.ResetTimeStamp(key))
+}
+
+func newestMenuKey(all map[string][]byte) string {
+	var (
+		newest string
+		stamp  int64 = -1
+	)
+	for key := range all {
+		if !strings.HasPrefix(key, MenuPrefix) || strings.HasSuffix(key, "_timestamp") {
+			continue
+		}
+		t, err := strconv.ParseInt(string(all[key+"_timestamp"]), 10, 64)
+		if err != nil {
+			t = 0
+		}
+		if t > stamp || (t == stamp && key < newest) {
+			newest, stamp = key, t
+		}
+	}
+	return newest
 }
 
 func init() {
@@ -51,6 +155,7 @@ type generalMenuCacher struct {
 	m        *dawg.Menu
 	db       cache.Storage
 	getstore func() *dawg.Store
+	getctx   func() context.Context
 
 	newEncoder func(io.Writer) Encoder
 	newDecoder func(io.Reader) Decoder
@@ -59,6 +164,7 @@ type generalMenuCacher struct {
 // NewJSONMenuCacher will create a new MenuCacher that stores the
 // menu as json.
 func NewJSONMenuCacher(
+	ctx func() context.Context,
 	decay time.Duration,
 	db cache.Storage,
 	store func() *dawg.Store,
@@ -67,6 +173,7 @@ func NewJSONMenuCacher(
 		m:          nil,
 		db:         db,
 		getstore:   store,
+		getctx:     ctx,
 		newEncoder: func(w io.Writer) Encoder { return json.NewEncoder(w) },
 		newDecoder: func(r io.Reader) Decoder { return json.NewDecoder(r) },
 	}
@@ -77,6 +184,7 @@ func NewJSONMenuCacher(
 // NewGobMenuCacher will create a MenuCacher that will store the menu
 // in a binary format using the "encoding/gob" package.
 func NewGobMenuCacher(
+	ctx func() context.Context,
 	decay time.Duration,
 	db cache.Storage,
 	store func() *dawg.Store,
@@ -85,6 +193,7 @@ func NewGobMenuCacher(
 		m:          nil,
 		db:         db,
 		getstore:   store,
+		getctx:     ctx,
 		newEncoder: func(w io.Writer) Encoder { return gob.NewEncoder(w) },
 		newDecoder: func(r io.Reader) Decoder { return gob.NewDecoder(r) },
 	}
@@ -99,20 +208,32 @@ func (mc *generalMenuCacher) Menu() *dawg.Menu {
 	return nil
 }
 
+func (mc *generalMenuCacher) CacheKey() string {
+	return MenuKey(mc.getstore().ID)
+}
+
 func (mc *generalMenuCacher) cacheNewMenu() error {
 	var e1, e2 error
-	mc.m, e1 = mc.getstore().Menu()
+	store := mc.getstore()
+	progress := cli.ProgressFrom(mc.getctx())
+	progress.Start("downloading the menu")
+	mc.m, e1 = store.MenuContext(mc.getctx())
+	progress.Stop()
 	log.Println("caching another menu")
 
 	buf := &bytes.Buffer{}
 	e2 = mc.newEncoder(buf).Encode(mc.m)
-	return errs.Append(e1, e2, mc.db.Put("menu", buf.Bytes()))
+	if d, ok := mc.db.(cache.Deleter); ok {
+		// the old global menu is never read so it can be removed
+		d.Delete(legacyMenuKey)
+	}
+	return errs.Append(e1, e2, mc.db.Put(mc.CacheKey(), buf.Bytes()))
 }
 
 func (mc *generalMenuCacher) getCachedMenu() error {
 	if mc.m == nil {
 		mc.m = new(dawg.Menu)
-		raw, err := mc.db.Get("menu")
+		raw, err := mc.db.Get(mc.CacheKey())
 		if raw == nil {
 			return mc.cacheNewMenu()
 		}
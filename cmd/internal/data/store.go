@@ -0,0 +1,74 @@
+package data
+
+import (
+	"encoding/json"
+
+	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+const (
+	// StoreIDKey is the database key used to cache the id of the
+	// selected store.
+	StoreIDKey = "store_id"
+
+	// StoreAddressKey is the database key used to cache the address
+	// that the selected store was found for.
+	StoreAddressKey = "store_address"
+)
+
+// SaveStore caches the id of a store along with the address it was found
+// for so that the same store can be used until the address changes.
+func SaveStore(db *cache.DataBase, id string, addr dawg.Address) error {
+	raw, err := json.Marshal(obj.FromAddress(addr))
+	if err != nil {
+		return err
+	}
+	if err = db.Put(StoreAddressKey, raw); err != nil {
+		return err
+	}
+	return db.Put(StoreIDKey, []byte(id))
+}
+
+// SavedStore returns the id of the cached store and the address it was found
+// for. The id is empty if there is no cached store.
+func SavedStore(db *cache.DataBase) (string, *obj.Address, error) {
+	id, err := db.Get(StoreIDKey)
+	if err != nil || len(id) == 0 {
+		return "", nil, err
+	}
+	addr := &obj.Address{}
+	raw, err := db.Get(StoreAddressKey)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(raw) > 0 {
+		if err = json.Unmarshal(raw, addr); err != nil {
+			return "", nil, err
+		}
+	}
+	return string(id), addr, nil
+}
+
+// StoreFor returns the id of the cached store if it was found for the
+// address addr. If the address has changed, the cached store is removed
+// and the id will be empty.
+func StoreFor(db *cache.DataBase, addr dawg.Address) (string, error) {
+	id, saved, err := SavedStore(db)
+	if err != nil || id == "" {
+		return "", err
+	}
+	if *saved != *obj.FromAddress(addr) {
+		return "", ForgetStore(db)
+	}
+	return id, nil
+}
+
+// ForgetStore removes the cached store.
+func ForgetStore(db *cache.DataBase) error {
+	if err := db.Delete(StoreIDKey); err != nil {
+		return err
+	}
+	return db.Delete(StoreAddressKey)
+}
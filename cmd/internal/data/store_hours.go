@@ -0,0 +1,60 @@
+package data
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+// StoreHoursPrefix is the prefix for the database keys used to cache
+// store hours.
+const StoreHoursPrefix = "store_hours_"
+
+// StoreHoursTTL is how long store hours are cached for.
+var StoreHoursTTL = 24 * time.Hour
+
+// StoreHours are the hours of a store that are cached in the database.
+type StoreHours struct {
+	Hours        dawg.StoreHours            `json:"hours"`
+	ServiceHours map[string]dawg.StoreHours `json:"service_hours"`
+
+	// FutureOrders has the store's time zone and its
+	// limits for scheduled orders.
+	FutureOrders dawg.FutureOrders `json:"future_orders"`
+}
+
+// HoursFromStore gets the hours of a store.
+func HoursFromStore(s *dawg.Store) *StoreHours {
+	return &StoreHours{Hours: s.Hours, ServiceHours: s.ServiceHours, FutureOrders: s.FutureOrders}
+}
+
+// For returns the hours of a service method or the store's
+// hours if there are none for that service.
+func (h *StoreHours) For(service string) *dawg.StoreHours {
+	if hours, ok := h.ServiceHours[service]; ok {
+		return &hours
+	}
+	return &h.Hours
+}
+
+// SaveStoreHours caches the hours of a store.
+func SaveStoreHours(db *cache.DataBase, s *dawg.Store) error {
+	raw, err := json.Marshal(HoursFromStore(s))
+	if err != nil {
+		return err
+	}
+	return db.PutWithTTL(StoreHoursPrefix+s.ID, raw, StoreHoursTTL)
+}
+
+// GetStoreHours gets the cached hours of a store. It will return
+// nil if the hours are not cached or they have expired.
+func GetStoreHours(db *cache.DataBase, id string) (*StoreHours, error) {
+	raw, expired, err := db.GetWithExpiry(StoreHoursPrefix + id)
+	if err != nil || expired || len(raw) == 0 {
+		return nil, err
+	}
+	h := &StoreHours{}
+	return h, json.Unmarshal(raw, h)
+}
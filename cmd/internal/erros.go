@@ -1,12 +1,28 @@
 package internal
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 var (
 	// ErrNoAddress is the error found when the cli could no find an address
 	ErrNoAddress = errors.New("no address found. (see 'apizza address' or 'apizza config')")
 
+	// ErrTimeout is the error returned when a request to dominos takes
+	// longer than the time given by the --timeout flag.
+	ErrTimeout = errors.New("request to dominos timed out (see '--timeout')")
+
 	// ErrNoOrderName is the error raised when the is no order name given to the
 	// cart or the order commands.
 	ErrNoOrderName = errors.New("No order name... use '--name=<order name>' or give name as an argument")
 )
+
+// TimeoutErr will return ErrTimeout if the error was caused by a context
+// reaching its deadline, otherwise the original error is returned.
+func TimeoutErr(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	return err
+}
@@ -0,0 +1,35 @@
+package out
+
+// color is set by SetColor, it is only used by the
+// functions that print human readable output.
+var color = false
+
+// SetColor turns color on or off for the menus and orders printed by this
+// package. Json and --format output is never colored. ResetOutput turns
+// color off again.
+func SetColor(enabled bool) {
+	color = enabled
+}
+
+const (
+	ansiBold  = "\033[1m"
+	ansiCyan  = "\033[36m"
+	ansiReset = "\033[0m"
+)
+
+func paint(code, s string) string {
+	if !color || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// bold is used for names and headings.
+func bold(s string) string {
+	return paint(ansiBold, s)
+}
+
+// highlight is used for the item codes that can be given to other commands.
+func highlight(s string) string {
+	return paint(ansiCyan, s)
+}
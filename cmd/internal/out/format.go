@@ -0,0 +1,130 @@
+package out
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/harrybrwn/apizza/dawg"
+)
+
+// Item is the data given to a template from the --format flag for each item
+// that is printed.
+type Item struct {
+	Name     string
+	Code     string
+	Price    string
+	Category string
+
+	// Qty and Options are only set for the products in an order.
+	Qty     int
+	Options map[string]string
+
+	// Variants are the sizes of a product on the menu.
+	Variants []Item
+}
+
+// ParseFormat parses a template given to the --format flag.
+func ParseFormat(format string) (*template.Template, error) {
+	t, err := template.New("format").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("bad --format template: %v", err)
+	}
+	return t, nil
+}
+
+// PrintFormat renders each item with a template from ParseFormat and writes
+// them to the package output, one item per line.
+func PrintFormat(t *template.Template, items []Item) error {
+	for _, item := range items {
+		if err := t.Execute(output, item); err != nil {
+			return fmt.Errorf("could not use --format template: %v", err)
+		}
+		if _, err := fmt.Fprintln(output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MenuItems returns the items in a list of menu categories and all of their
+// sub-categories in the order that they are printed by PrintMenu.
+func MenuItems(cats []dawg.MenuCategory, m *dawg.Menu) []Item {
+	var items []Item
+	for _, cat := range cats {
+		if cat.HasItems() {
+			items = append(items, MenuCodeItems(cat.Products, m)...)
+		} else {
+			items = append(items, MenuItems(cat.Categories, m)...)
+		}
+	}
+	return items
+}
+
+// MenuCodeItems returns the items for a list of menu item codes. Codes that
+// are not on the menu are skipped.
+func MenuCodeItems(codes []string, m *dawg.Menu) []Item {
+	items := make([]Item, 0, len(codes))
+	for _, code := range codes {
+		if item, ok := MenuItem(m.FindItem(code), m); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// MenuItem converts a menu item to an Item. Products with only one variant
+// are given as that variant, the same as they are printed by PrintMenu.
+func MenuItem(i dawg.Item, m *dawg.Menu) (Item, bool) {
+	switch p := i.(type) {
+	case *dawg.Product:
+		if len(p.Variants) == 1 {
+			if v, err := m.GetVariant(p.Variants[0]); err == nil {
+				return variantItem(v), true
+			}
+		}
+		item := Item{Name: p.Name, Code: p.Code, Category: p.Category()}
+		for _, code := range p.Variants {
+			if v, err := m.GetVariant(code); err == nil {
+				item.Variants = append(item.Variants, variantItem(v))
+			}
+		}
+		return item, true
+	case *dawg.Variant:
+		return variantItem(p), true
+	case *dawg.PreConfiguredProduct:
+		return Item{Name: p.Name, Code: p.Code, Category: p.Category()}, true
+	}
+	return Item{}, false
+}
+
+// OrderItems returns the items for each product in an order. The prices are
+// found using the menu if it is not nil.
+func OrderItems(o *dawg.Order, m *dawg.Menu) []Item {
+	items := make([]Item, 0, len(o.Products))
+	for _, p := range o.Products {
+		item := Item{
+			Name:    p.Name,
+			Code:    p.Code,
+			Qty:     p.Qty,
+			Options: p.ReadableOptions(),
+		}
+		if m != nil {
+			if v, err := m.GetVariant(p.Code); err == nil {
+				item.Price = v.Price
+				if item.Name == "" {
+					item.Name = v.Name
+				}
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func variantItem(v *dawg.Variant) Item {
+	item := Item{Name: v.Name, Code: v.Code, Price: v.Price}
+	if parent := v.GetProduct(); parent != nil {
+		item.Category = parent.Category()
+	}
+	return item
+}
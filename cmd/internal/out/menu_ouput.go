@@ -2,6 +2,7 @@ package out
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"unicode/utf8"
@@ -16,7 +17,7 @@ func PrintMenu(cat dawg.MenuCategory, depth int, m *dawg.Menu) error {
 		return nil
 	}
 	fmt.Fprintf(output, "\n%s%s%s%s\n", spaces(depth*2), strings.Repeat("-", 8),
-		cat.Name, strings.Repeat("-", 60-len(cat.Name)-(depth*2)))
+		bold(cat.Name), strings.Repeat("-", 60-len(cat.Name)-(depth*2)))
 	if cat.HasItems() {
 		for _, p := range cat.Products {
 			printCategory(p, depth+1, m)
@@ -29,6 +30,13 @@ func PrintMenu(cat dawg.MenuCategory, depth int, m *dawg.Menu) error {
 	return nil
 }
 
+// PrintItems prints a list of menu items given their item codes.
+func PrintItems(codes []string, depth int, m *dawg.Menu) {
+	for _, code := range codes {
+		printCategory(code, depth, m)
+	}
+}
+
 // ItemInfo prints the common information for an Item.
 // Used by the menu command.
 func ItemInfo(prod dawg.Item, menu *dawg.Menu) error {
@@ -56,8 +64,8 @@ func ItemInfo(prod dawg.Item, menu *dawg.Menu) error {
 }
 
 func iteminfo(i dawg.Item, menu *dawg.Menu) {
-	fmt.Fprintf(output, "%s\n", i.ItemName())
-	fmt.Fprintf(output, "  Code: %s\n", i.ItemCode())
+	fmt.Fprintf(output, "%s\n", bold(i.ItemName()))
+	fmt.Fprintf(output, "  Code: %s\n", highlight(i.ItemCode()))
 	if c := i.Category(); c != "" {
 		fmt.Fprintf(output, "  Category: %s\n", c)
 	}
@@ -81,11 +89,11 @@ func printCategory(code string, indent int, m *dawg.Menu) {
 				panic(err)
 			}
 
-			fmt.Fprintf(output, "%s%s  %s\n", spaces(indent*2), p.Code, p.Name)
+			fmt.Fprintf(output, "%s%s  %s\n", spaces(indent*2), highlight(p.Code), p.Name)
 			return
 		}
 
-		fmt.Fprintf(output, "%s%s [%s]\n", spaces(indent*2), item.ItemName(), item.ItemCode())
+		fmt.Fprintf(output, "%s%s [%s]\n", spaces(indent*2), bold(item.ItemName()), highlight(item.ItemCode()))
 		n := maxStrLen(product.Variants)
 		for _, variant := range product.Variants {
 			v, err := m.GetVariant(variant)
@@ -93,13 +101,13 @@ func printCategory(code string, indent int, m *dawg.Menu) {
 				continue
 			}
 			fmt.Fprintf(output, "%s%s %s %s\n",
-				spaces(2*(indent+1)), variant,
+				spaces(2*(indent+1)), highlight(variant),
 				spaces(n-strLen(variant)), v.Name)
 		}
 
 	case *dawg.PreConfiguredProduct:
 		fmt.Fprintf(output, "%s%s   %s\n", spaces(indent*2),
-			item.ItemCode(), item.ItemName())
+			highlight(item.ItemCode()), item.ItemName())
 	}
 }
 
@@ -124,3 +132,91 @@ func spaces(i int) string {
 }
 
 var strLen = utf8.RuneCountInString // this is a function
+
+type jsonMenuItem struct {
+	Code     string         `json:"code"`
+	Name     string         `json:"name"`
+	Price    string         `json:"price,omitempty"`
+	Variants []jsonMenuItem `json:"variants,omitempty"`
+}
+
+type jsonMenuCategory struct {
+	Name       string             `json:"name"`
+	Code       string             `json:"code"`
+	Items      []jsonMenuItem     `json:"items"`
+	Categories []jsonMenuCategory `json:"categories"`
+}
+
+// PrintMenuJSON writes a list of menu categories to the package output as
+// json. Empty categories are written with empty lists so that the output is
+// always valid json.
+func PrintMenuJSON(cats []dawg.MenuCategory, m *dawg.Menu) error {
+	all := make([]jsonMenuCategory, 0, len(cats))
+	for _, cat := range cats {
+		all = append(all, jsonCategory(cat, m))
+	}
+	return json.NewEncoder(output).Encode(all)
+}
+
+// PrintItemJSON writes a single menu item to the package output as json.
+func PrintItemJSON(i dawg.Item, m *dawg.Menu) error {
+	item, ok := jsonItem(i.ItemCode(), m)
+	if !ok {
+		return fmt.Errorf("cannot find %s", i.ItemCode())
+	}
+	return json.NewEncoder(output).Encode(item)
+}
+
+// PrintItemsJSON writes a list of menu items to the package output as json.
+// Codes that are not on the menu are skipped.
+func PrintItemsJSON(codes []string, m *dawg.Menu) error {
+	items := make([]jsonMenuItem, 0, len(codes))
+	for _, code := range codes {
+		if item, ok := jsonItem(code, m); ok {
+			items = append(items, item)
+		}
+	}
+	return json.NewEncoder(output).Encode(items)
+}
+
+func jsonCategory(cat dawg.MenuCategory, m *dawg.Menu) jsonMenuCategory {
+	c := jsonMenuCategory{
+		Name:       cat.Name,
+		Code:       cat.Code,
+		Items:      []jsonMenuItem{},
+		Categories: []jsonMenuCategory{},
+	}
+	for _, code := range cat.Products {
+		if item, ok := jsonItem(code, m); ok {
+			c.Items = append(c.Items, item)
+		}
+	}
+	for _, sub := range cat.Categories {
+		c.Categories = append(c.Categories, jsonCategory(sub, m))
+	}
+	return c
+}
+
+func jsonItem(code string, m *dawg.Menu) (jsonMenuItem, bool) {
+	item := jsonMenuItem{Code: code}
+	switch product := m.FindItem(code).(type) {
+	case *dawg.Product:
+		item.Name = product.Name
+		for _, vcode := range product.Variants {
+			v, err := m.GetVariant(vcode)
+			if err != nil {
+				continue
+			}
+			item.Variants = append(item.Variants, jsonMenuItem{
+				Code: v.Code, Name: v.Name, Price: v.Price,
+			})
+		}
+	case *dawg.Variant:
+		item.Name, item.Price = product.Name, product.Price
+	case *dawg.PreConfiguredProduct:
+		item.Name = product.Name
+	default:
+		return item, false
+	}
+	return item, true
+}
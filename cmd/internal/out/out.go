@@ -26,8 +26,10 @@ func SetOutput(w io.Writer) {
 }
 
 // ResetOutput will reset the package output to it's default io.Writer
+// and turn off color.
 func ResetOutput() {
 	output = _output
+	color = false
 }
 
 // FormatLine will take a string and make sure it does not cross a certain length
@@ -2,6 +2,7 @@ package out
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
@@ -39,6 +40,44 @@ func TestTmpl(t *testing.T) {
 	}
 }
 
+func TestColor(t *testing.T) {
+	tests.InitHelpers(t)
+	defer ResetOutput()
+	buf := new(bytes.Buffer)
+	SetOutput(buf)
+	menu := &dawg.Menu{
+		Products: map[string]*dawg.Product{
+			"S_PIZZA": {ItemCommon: dawg.ItemCommon{Code: "S_PIZZA", Name: "Pizza"}, Variants: []string{"12SCREEN"}},
+		},
+		Variants: map[string]*dawg.Variant{
+			"12SCREEN": {ItemCommon: dawg.ItemCommon{Code: "12SCREEN", Name: "Medium Pizza"}},
+		},
+	}
+	cat := dawg.MenuCategory{Name: "Pizza", Products: []string{"S_PIZZA"}}
+
+	tests.Check(PrintMenu(cat, 0, menu))
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("output should not be colored by default, got %q", buf.String())
+	}
+	buf.Reset()
+	SetColor(true)
+	tests.Check(PrintMenu(cat, 0, menu))
+	for _, s := range []string{ansiBold + "Pizza" + ansiReset, ansiCyan + "12SCREEN" + ansiReset} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("colored menu should contain %q, got %q", s, buf.String())
+		}
+	}
+	buf.Reset()
+	tests.Check(PrintMenuJSON([]dawg.MenuCategory{cat}, menu))
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("json should never be colored, got %q", buf.String())
+	}
+	ResetOutput()
+	if color {
+		t.Error("ResetOutput should turn off color")
+	}
+}
+
 var testStore *dawg.Store
 
 func init() {
@@ -128,3 +167,99 @@ func TestPrintMenu(t *testing.T) {
 		t.Error("menu output is too short")
 	}
 }
+
+func TestPrintMenuJSON(t *testing.T) {
+	tests.InitHelpers(t)
+	menu := &dawg.Menu{
+		Products: map[string]*dawg.Product{
+			"S_PIZZA": {
+				ItemCommon: dawg.ItemCommon{Code: "S_PIZZA", Name: "Pizza"},
+				Variants:   []string{"10SCREEN"},
+			},
+		},
+		Variants: map[string]*dawg.Variant{
+			"10SCREEN": {
+				ItemCommon:  dawg.ItemCommon{Code: "10SCREEN", Name: "Small Pizza"},
+				Price:       "9.99",
+				ProductCode: "S_PIZZA",
+			},
+		},
+	}
+	cats := []dawg.MenuCategory{
+		{Name: "Pizza", Code: "Pizza", Products: []string{"S_PIZZA"}},
+		{Name: "Empty", Code: "Empty"},
+	}
+	buf := new(bytes.Buffer)
+	SetOutput(buf)
+	defer ResetOutput()
+
+	tests.Check(PrintMenuJSON(cats, menu))
+	expected := `[{"name":"Pizza","code":"Pizza","items":[{"code":"S_PIZZA","name":"Pizza","variants":[{"code":"10SCREEN","name":"Small Pizza","price":"9.99"}]}],"categories":[]},{"name":"Empty","code":"Empty","items":[],"categories":[]}]` + "\n"
+	tests.Compare(t, buf.String(), expected)
+
+	buf.Reset()
+	tests.Check(PrintItemJSON(menu.Variants["10SCREEN"], menu))
+	tests.Compare(t, buf.String(), `{"code":"10SCREEN","name":"Small Pizza","price":"9.99"}`+"\n")
+	buf.Reset()
+	tests.Check(PrintItemsJSON([]string{"10SCREEN", "NOTATHING"}, menu))
+	tests.Compare(t, buf.String(), `[{"code":"10SCREEN","name":"Small Pizza","price":"9.99"}]`+"\n")
+	buf.Reset()
+	tests.Check(PrintItemsJSON(nil, menu))
+	tests.Compare(t, buf.String(), "[]\n")
+}
+
+func TestPrintFormat(t *testing.T) {
+	tests.InitHelpers(t)
+	menu := &dawg.Menu{
+		Products: map[string]*dawg.Product{
+			"S_PIZZA": {
+				ItemCommon:  dawg.ItemCommon{Code: "S_PIZZA", Name: "Pizza"},
+				ProductType: "Pizza",
+				Variants:    []string{"10SCREEN", "12SCREEN"},
+			},
+			"S_COKE": {
+				ItemCommon:  dawg.ItemCommon{Code: "S_COKE", Name: "Coke"},
+				ProductType: "Drinks",
+				Variants:    []string{"20BCOKE"},
+			},
+		},
+		Variants: map[string]*dawg.Variant{
+			"10SCREEN": {ItemCommon: dawg.ItemCommon{Code: "10SCREEN", Name: "Small Pizza"}, Price: "9.99", ProductCode: "S_PIZZA"},
+			"12SCREEN": {ItemCommon: dawg.ItemCommon{Code: "12SCREEN", Name: "Medium Pizza"}, Price: "11.99", ProductCode: "S_PIZZA"},
+			"20BCOKE":  {ItemCommon: dawg.ItemCommon{Code: "20BCOKE", Name: "20oz Coke"}, Price: "2.09", ProductCode: "S_COKE"},
+		},
+	}
+	cats := []dawg.MenuCategory{
+		{Name: "Food", Categories: []dawg.MenuCategory{
+			{Name: "Pizza", Products: []string{"S_PIZZA"}},
+			{Name: "Drinks", Products: []string{"S_COKE", "NOTHERE"}},
+		}},
+	}
+	buf := new(bytes.Buffer)
+	SetOutput(buf)
+	defer ResetOutput()
+
+	f, err := ParseFormat("{{.Code}} {{.Name}} ${{.Price}} {{.Category}}{{range .Variants}} {{.Code}}{{end}}")
+	tests.Check(err)
+	tests.Check(PrintFormat(f, MenuItems(cats, menu)))
+	tests.Compare(t, buf.String(), "S_PIZZA Pizza $ Pizza 10SCREEN 12SCREEN\n20BCOKE 20oz Coke $2.09 Drinks\n")
+	buf.Reset()
+
+	o := &dawg.Order{Products: []*dawg.OrderProduct{
+		{ItemCommon: dawg.ItemCommon{Code: "12SCREEN"}, Qty: 2},
+	}}
+	f, err = ParseFormat("{{.Qty}} x {{.Name}} ({{.Code}}) ${{.Price}}")
+	tests.Check(err)
+	tests.Check(PrintFormat(f, OrderItems(o, menu)))
+	tests.Compare(t, buf.String(), "2 x Medium Pizza (12SCREEN) $11.99\n")
+
+	if _, err = ParseFormat("{{.Code"); err == nil {
+		t.Error("expected an error for a malformed template")
+	} else if !strings.HasPrefix(err.Error(), "bad --format template:") {
+		t.Error("wrong error message, got:", err.Error())
+	}
+	f, err = ParseFormat("{{.Nope}}")
+	tests.Check(err)
+	tests.Exp(PrintFormat(f, OrderItems(o, menu)), "expected an error for a field that does not exist")
+	tests.Exp(tmpl(buf, "{{.Code", nil), "tmpl should not panic on a bad template")
+}
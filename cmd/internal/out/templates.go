@@ -3,23 +3,27 @@ package out
 import (
 	"io"
 	"text/template"
-
-	"github.com/harrybrwn/apizza/pkg/errs"
 )
 
 func tmpl(w io.Writer, tmplt string, a interface{}) (err error) {
-	t := template.New("apizza")
-	t, err = t.Parse(tmplt)
-	return errs.Pair(err, t.Execute(w, a))
+	t, err := template.New("apizza").Funcs(template.FuncMap{
+		"bold": bold,
+		"code": highlight,
+	}).Parse(tmplt)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, a)
 }
 
-var defaultOrderTmpl = `{{ .OrderName }}
+var defaultOrderTmpl = `{{ bold .OrderName }}
   products:{{ range .Products }}
     {{.Name}}
-      code:     {{.Code}}
+      code:     {{code .Code}}
       options:{{ range $k, $v := .ReadableOptions }}
          {{$k}}: {{$v}}{{else}}None{{end}}
-      quantity: {{.Qty}}{{end}}
+      quantity: {{.Qty}}{{end}}{{ if .Coupons }}
+  coupons:{{ range .Coupons }} {{.Code}}{{end}}{{end}}
   storeID: {{.StoreID}}
   method:  {{.ServiceMethod}}
   address: {{.Addr -}}
@@ -28,7 +32,7 @@ var defaultOrderTmpl = `{{ .OrderName }}
 {{else}}{{end}}
 `
 
-var cartOrderTmpl = `  {{ .OrderName }} - {{ range .Products }} {{.Code}}, {{end}}
+var cartOrderTmpl = `  {{ bold .OrderName }} - {{ range .Products }} {{code .Code}}, {{end}}
 `
 
 var menuCategoryTmpl = ``
@@ -15,17 +15,24 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
 	"unicode/utf8"
 
 	"github.com/harrybrwn/apizza/cmd/cli"
 	"github.com/harrybrwn/apizza/cmd/client"
+	"github.com/harrybrwn/apizza/cmd/internal"
 	"github.com/harrybrwn/apizza/cmd/internal/data"
 	"github.com/harrybrwn/apizza/cmd/internal/out"
+	"github.com/harrybrwn/apizza/cmd/opts"
 	"github.com/harrybrwn/apizza/pkg/cache"
 	"github.com/harrybrwn/apizza/pkg/errs"
 	"github.com/spf13/cobra"
@@ -40,27 +47,83 @@ type menuCmd struct {
 	data.MenuCacher
 	client.StoreFinder
 
-	db *cache.DataBase
+	db    *cache.DataBase
+	gopts *opts.CliFlags
 
-	addr dawg.Address
+	// menu is a cached menu that is used instead of
+	// the MenuCacher when browsing categories
+	menu *dawg.Menu
+
+	addr       dawg.Address
+	getctx     func() context.Context
+	getservice func() string
+	color      func() bool
 
 	all            bool
 	page           bool
-	verbose        bool
 	toppings       bool
 	preconfigured  bool
 	showCategories bool
+	json           bool
 	item           string
 	category       string
+	search         []string
+	format         string
+	force          bool
+	raw            bool
+	diff           bool
+	update         bool
+
+	tmpl *template.Template
 }
 
 func (c *menuCmd) Run(cmd *cobra.Command, args []string) error {
-	if err := c.db.UpdateTS("menu", c); err != nil {
-		return err
+	if c.raw {
+		// the menu is always downloaded so that it is not from the cache
+		ctx := dawg.WithRawResponses(c.getctx(), c.Output())
+		_, err := c.Store().MenuContext(ctx)
+		return internal.TimeoutErr(err)
+	}
+	if c.update && !c.diff {
+		return errors.New("--update can only be used with --diff")
+	}
+	if c.diff {
+		return c.diffMenu()
+	}
+	c.menu = nil
+	if c.showCategories || c.category != "" {
+		// categories are browsed with the cached menu so that it works offline
+		if m, err := data.CachedMenu(c.db, c.gopts.StoreID); err == nil {
+			c.menu = m
+		}
+	}
+	if c.menu == nil {
+		if !c.force {
+			err := client.CheckStoreHours(c.getctx(), c.db, c.Store().ID, c.getservice(), time.Now())
+			if err != nil {
+				return err
+			}
+		}
+		if err := c.db.UpdateTS(c.CacheKey(), c); err != nil {
+			return err
+		}
 	}
 	out.SetOutput(c.Output())
+	out.SetColor(c.color() && !c.json)
 	defer out.ResetOutput()
 
+	c.tmpl = nil
+	if c.format != "" {
+		if c.json {
+			return errors.New("cannot use --format with --json")
+		}
+		tmpl, err := out.ParseFormat(c.format)
+		if err != nil {
+			return err
+		}
+		c.tmpl = tmpl
+	}
+
 	var item dawg.Item
 
 	if len(args) == 1 {
@@ -71,7 +134,7 @@ func (c *menuCmd) Run(cmd *cobra.Command, args []string) error {
 		if item == nil && c.category == "" {
 			c.category = strings.ToLower(args[0])
 		} else {
-			return out.ItemInfo(item, c.Menu())
+			return c.itemInfo(item)
 		}
 	}
 
@@ -80,14 +143,25 @@ func (c *menuCmd) Run(cmd *cobra.Command, args []string) error {
 		if prod == nil {
 			return fmt.Errorf("cannot find %s", c.item)
 		}
-		return out.ItemInfo(prod, c.Menu())
+		return c.itemInfo(prod)
 	}
 
 	if c.toppings {
+		if c.json {
+			return errors.New("cannot use --toppings with --json")
+		}
 		c.printToppings()
 		return nil
 	}
 
+	if len(c.search) > 0 {
+		return c.printSearch(c.search)
+	}
+
+	if c.json {
+		return c.printMenuJSON(strings.ToLower(c.category))
+	}
+
 	// print menu handles most of the menu command's flags
 	if c.page {
 		return c.pageMenu(strings.ToLower(c.category))
@@ -99,6 +173,10 @@ func (c *menuCmd) Run(cmd *cobra.Command, args []string) error {
 func NewMenuCmd(b cli.Builder) cli.CliCommand {
 	c := &menuCmd{
 		db:             b.DB(),
+		gopts:          b.GlobalOptions(),
+		getctx:         b.Context,
+		getservice:     func() string { return cli.ServiceMethod(b) },
+		color:          b.Color,
 		all:            false,
 		toppings:       false,
 		preconfigured:  false,
@@ -111,18 +189,36 @@ func NewMenuCmd(b cli.Builder) cli.CliCommand {
 	}
 
 	c.CliCommand = b.Build("menu <item>", "View the Dominos menu.", c)
-	c.MenuCacher = data.NewMenuCacher(menuUpdateTime, b.DB(), c.Store)
+	c.MenuCacher = data.NewMenuCacher(b.Context, menuUpdateTime, b.DB(), c.Store)
 	c.SetOutput(b.Output())
 
 	c.Cmd().Long = `This command will show the dominos menu.
 
 To show a subdivision of the menu, give an item or
 category to the --category and --item flags or give them
-as an argument to the command itself.`
+as an argument to the command itself.
+
+Use --format to print each item with a go template. The template
+is given the item's .Name, .Code, .Price, .Category, and .Variants
+and each item is printed on its own line.
+
+  apizza menu pizza --format '{{.Code}}: {{.Name}}'
+
+The menu will not be shown when the store is closed, use --force
+to show it anyway.
+
+Use --categories to list the menu categories and --category to show one of
+them. Categories can be given by part of their name, ex. 'apizza menu -c pizz'.
+Both flags use the most recently cached menu so they work offline.
+
+Use --raw to print the menu exactly as dominos sent it.
+
+Use --diff to download the menu and compare it with the cached menu. Items that
+were added, removed, or changed price are printed. The cached menu is only
+replaced with the new one if --update is also given.`
 
 	flags := c.Flags()
 	flags.BoolVarP(&c.all, "all", "a", c.all, "show the entire menu")
-	flags.BoolVarP(&c.verbose, "verbose", "v", false, "print the menu verbosely")
 	flags.BoolVar(&c.page, "page", false, "pipe the menu to a pager")
 
 	flags.StringVarP(&c.item, "item", "i", "", "show info on the menu item given")
@@ -132,27 +228,53 @@ as an argument to the command itself.`
 	flags.BoolVarP(&c.preconfigured, "preconfigured",
 		"p", c.preconfigured, "show the pre-configured products on the dominos menu")
 	flags.BoolVar(&c.showCategories, "show-categories", c.showCategories, "print categories")
+	flags.BoolVar(&c.showCategories, "categories", c.showCategories, "list the menu categories")
+	flags.BoolVar(&c.json, "json", c.json, "print the menu, an item, or search results as json")
+	flags.StringSliceVarP(&c.search, "search", "s", nil, "search the menu for items matching any of the comma separated terms")
+	flags.StringVar(&c.format, "format", "", "print each item with a go template (ex. '{{.Code}} {{.Price}}')")
+	flags.BoolVar(&c.force, "force", false, "show the menu even if the store is closed")
+	flags.BoolVar(&c.raw, "raw", false, "print the unparsed menu sent by dominos")
+	flags.BoolVar(&c.diff, "diff", false, "compare the cached menu with the current menu from dominos")
+	flags.BoolVar(&c.update, "update", false, "replace the cached menu with the new one when using --diff")
 	return c
 }
 
+// Menu returns the cached menu when browsing categories
+// or the menu from the MenuCacher.
+func (c *menuCmd) Menu() *dawg.Menu {
+	if c.menu != nil {
+		return c.menu
+	}
+	return c.MenuCacher.Menu()
+}
+
+// itemInfo prints one menu item using the --format template if there is one.
+func (c *menuCmd) itemInfo(item dawg.Item) error {
+	if c.json {
+		return out.PrintItemJSON(item, c.Menu())
+	}
+	if c.tmpl == nil {
+		return out.ItemInfo(item, c.Menu())
+	}
+	i, _ := out.MenuItem(item, c.Menu())
+	return out.PrintFormat(c.tmpl, []out.Item{i})
+}
+
 func (c *menuCmd) printMenu(w io.Writer, name string) error {
 	out.SetOutput(w)
 	defer out.ResetOutput()
 	menu := c.Menu()
-	var allCategories = menu.Categorization.Food.Categories
-	if c.preconfigured {
-		allCategories = menu.Categorization.Preconfigured.Categories
-	} else if c.all {
-		allCategories = append(allCategories, menu.Categorization.Preconfigured.Categories...)
-	}
+	var allCategories = c.categories(menu)
 
 	if len(name) > 0 {
-		for _, cat := range allCategories {
-			if name == strings.ToLower(cat.Name) || name == strings.ToLower(cat.Code) {
-				return out.PrintMenu(cat, 0, menu)
-			}
+		cat, err := findCategory(allCategories, name)
+		if err != nil {
+			return err
+		}
+		if c.tmpl != nil {
+			return out.PrintFormat(c.tmpl, out.MenuItems([]dawg.MenuCategory{cat}, menu))
 		}
-		return fmt.Errorf("could not find %s", name)
+		return out.PrintMenu(cat, 0, menu)
 	} else if c.showCategories {
 		for _, cat := range allCategories {
 			if cat.Name != "" {
@@ -162,12 +284,226 @@ func (c *menuCmd) printMenu(w io.Writer, name string) error {
 		return nil
 	}
 
+	if c.tmpl != nil {
+		return out.PrintFormat(c.tmpl, out.MenuItems(allCategories, menu))
+	}
 	for _, cat := range allCategories {
 		out.PrintMenu(cat, 0, menu)
 	}
 	return nil
 }
 
+func (c *menuCmd) printMenuJSON(name string) error {
+	menu := c.Menu()
+	var allCategories = c.categories(menu)
+
+	if len(name) > 0 {
+		cat, err := findCategory(allCategories, name)
+		if err != nil {
+			return err
+		}
+		return out.PrintMenuJSON([]dawg.MenuCategory{cat}, menu)
+	}
+	return out.PrintMenuJSON(allCategories, menu)
+}
+
+// findCategory finds a category by its name or code, ignoring case. If no
+// category matches exactly, the categories that contain the name are used
+// and it is an error for more than one of them to match.
+func findCategory(cats []dawg.MenuCategory, name string) (dawg.MenuCategory, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	var matches []dawg.MenuCategory
+	for _, cat := range cats {
+		catName, code := strings.ToLower(cat.Name), strings.ToLower(cat.Code)
+		if name == catName || name == code {
+			return cat, nil
+		}
+		if strings.Contains(catName, name) || strings.Contains(code, name) {
+			matches = append(matches, cat)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return dawg.MenuCategory{}, fmt.Errorf("could not find %s (see 'apizza menu --categories')", name)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, cat := range matches {
+		names[i] = strings.ToLower(cat.Name)
+	}
+	return dawg.MenuCategory{}, fmt.Errorf("'%s' matches more than one category: %s", name, strings.Join(names, ", "))
+}
+
+// categories returns the top level menu categories selected by the
+// --all and --preconfigured flags.
+func (c *menuCmd) categories(menu *dawg.Menu) []dawg.MenuCategory {
+	var allCategories = menu.Categorization.Food.Categories
+	if c.preconfigured {
+		allCategories = menu.Categorization.Preconfigured.Categories
+	} else if c.all {
+		allCategories = append(allCategories, menu.Categorization.Preconfigured.Categories...)
+	}
+	return allCategories
+}
+
+func (c *menuCmd) printSearch(terms []string) error {
+	menu := c.Menu()
+	codes := searchMenu(menu, terms)
+	if c.json {
+		return out.PrintItemsJSON(codes, menu)
+	}
+	if len(codes) == 0 {
+		c.Printf("no items found matching '%s'\n", strings.Join(terms, ", "))
+		return nil
+	}
+	if c.tmpl != nil {
+		return out.PrintFormat(c.tmpl, out.MenuCodeItems(codes, menu))
+	}
+	out.PrintItems(codes, 0, menu)
+	return nil
+}
+
+// searchMenu returns the codes of all the products whose name or
+// description contains any one of the search terms, ignoring case.
+func searchMenu(m *dawg.Menu, terms []string) []string {
+	lowered := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			lowered = append(lowered, t)
+		}
+	}
+	matches := func(fields ...string) bool {
+		for _, f := range fields {
+			f = strings.ToLower(f)
+			for _, t := range lowered {
+				if strings.Contains(f, t) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	var codes []string
+	for code, p := range m.Products {
+		if matches(p.Name, p.Description) {
+			codes = append(codes, code)
+		}
+	}
+	for code, p := range m.Preconfigured {
+		if matches(p.Name, p.Description) {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// diffMenu compares the cached menu with a newly downloaded one and
+// prints the differences.
+func (c *menuCmd) diffMenu() error {
+	store := c.Store()
+	old, err := data.CachedMenu(c.db, store.ID)
+	if err == data.ErrNoCachedMenu {
+		return fmt.Errorf("there is no cached menu for store %s to compare with", store.ID)
+	} else if err != nil {
+		return err
+	}
+	progress := cli.ProgressFrom(c.getctx())
+	progress.Start("downloading the menu")
+	menu, err := store.MenuContext(c.getctx())
+	progress.Stop()
+	if err != nil {
+		return internal.TimeoutErr(err)
+	}
+	printMenuDiff(c.Output(), diffMenus(old, menu))
+	if !c.update {
+		return nil
+	}
+	if err = data.SaveMenu(c.db, store.ID, menu); err != nil {
+		return err
+	}
+	c.Printf("the cached menu for store %s was updated\n", store.ID)
+	return nil
+}
+
+// menuDiff holds the items that are different between two menus.
+type menuDiff struct {
+	added, removed []*dawg.Variant
+	// changed holds the old and new versions of
+	// items that have a different price
+	changed [][2]*dawg.Variant
+}
+
+func (d *menuDiff) empty() bool {
+	return len(d.added) == 0 && len(d.removed) == 0 && len(d.changed) == 0
+}
+
+// diffMenus finds the items that were added, removed, or changed price
+// between the old and latest menu. Each list is sorted by item code.
+func diffMenus(old, latest *dawg.Menu) *menuDiff {
+	d := &menuDiff{}
+	for _, code := range sortedVariants(latest) {
+		v := latest.Variants[code]
+		prev, ok := old.Variants[code]
+		if !ok {
+			d.added = append(d.added, v)
+		} else if !samePrice(prev.Price, v.Price) {
+			d.changed = append(d.changed, [2]*dawg.Variant{prev, v})
+		}
+	}
+	for _, code := range sortedVariants(old) {
+		if _, ok := latest.Variants[code]; !ok {
+			d.removed = append(d.removed, old.Variants[code])
+		}
+	}
+	return d
+}
+
+func sortedVariants(m *dawg.Menu) []string {
+	codes := make([]string, 0, len(m.Variants))
+	for code := range m.Variants {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func samePrice(a, b string) bool {
+	x, e1 := strconv.ParseFloat(a, 64)
+	y, e2 := strconv.ParseFloat(b, 64)
+	if e1 != nil || e2 != nil {
+		return a == b
+	}
+	return x == y
+}
+
+func printMenuDiff(w io.Writer, d *menuDiff) {
+	if d.empty() {
+		fmt.Fprintln(w, "the menu has not changed")
+		return
+	}
+	if len(d.added) > 0 {
+		fmt.Fprintln(w, "added:")
+		for _, v := range d.added {
+			fmt.Fprintf(w, "  %-10s %8s  %s\n", v.Code, "$"+v.Price, v.Name)
+		}
+	}
+	if len(d.removed) > 0 {
+		fmt.Fprintln(w, "removed:")
+		for _, v := range d.removed {
+			fmt.Fprintf(w, "  %-10s %8s  %s\n", v.Code, "$"+v.Price, v.Name)
+		}
+	}
+	if len(d.changed) > 0 {
+		fmt.Fprintln(w, "price changes:")
+		for _, c := range d.changed {
+			fmt.Fprintf(w, "  %-10s %8s -> %-8s %s\n", c[1].Code, "$"+c[0].Price, "$"+c[1].Price, c[1].Name)
+		}
+	}
+}
+
 func (c *menuCmd) printToppings() {
 	var tops = c.Menu().Toppings
 
@@ -191,6 +527,10 @@ func (c *menuCmd) printToppings() {
 
 func (c *menuCmd) pageMenu(category string) error {
 	less := exec.Command("less")
+	if c.color() {
+		// let less show the color codes
+		less.Args = append(less.Args, "-R")
+	}
 	less.Stdout = c.Output()
 	stdin, err := less.StdinPipe()
 	if err != nil {
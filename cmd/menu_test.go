@@ -1,19 +1,30 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
 	"github.com/harrybrwn/apizza/pkg/tests"
 )
 
 func TestMenuRun(t *testing.T) {
 	tests.InitHelpers(t)
+	srv := cmdtest.NewServer()
+	defer srv.Close()
 	r := cmdtest.NewRecorder()
 	defer r.CleanUp()
 	c := NewMenuCmd(r).(*menuCmd)
 
 	tests.Check(c.Run(c.Cmd(), []string{}))
+	if !r.Contains("Hand Tossed") {
+		t.Errorf("the menu should have been printed, got %q", r.Out.String())
+	}
 	c.item = "not a thing"
 	tests.Exp(c.Run(c.Cmd(), []string{}))
 	c.item = "10SCREEN"
@@ -21,14 +32,94 @@ func TestMenuRun(t *testing.T) {
 	c.item = ""
 	c.toppings = true
 	tests.Check(c.Run(c.Cmd(), []string{}))
+	c.toppings = false
+
+	// the menu is cached so --raw has to download it again
+	r.ClearBuf()
+	c = NewMenuCmd(r).(*menuCmd)
+	c.raw = true
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	r.Compare(t, cmdtest.MenuJSON+"\n")
+	if n := srv.Count("/power/store/4336/menu"); n != 2 {
+		t.Errorf("--raw should download the menu again, it was downloaded %d times", n)
+	}
+}
+
+func TestMenuColor(t *testing.T) {
+	tests.InitHelpers(t)
+	srv := cmdtest.NewServer()
+	defer srv.Close()
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewMenuCmd(r).(*menuCmd)
+
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	if r.Contains("\033[") {
+		t.Error("the menu should not be colored unless color is enabled")
+	}
+	r.ClearBuf()
+	r.ColorOutput = true
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	if !r.Contains("\033[1mPizza\033[0m") {
+		t.Errorf("the menu should be colored, got %q", r.Out.String())
+	}
+	r.ClearBuf()
+	c.json = true
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	if r.Contains("\033[") {
+		t.Errorf("json output should never be colored, got %q", r.Out.String())
+	}
+}
+
+func TestMenuJSON(t *testing.T) {
+	tests.InitHelpers(t)
+	srv := cmdtest.NewServer()
+	defer srv.Close()
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewMenuCmd(r).(*menuCmd)
+	c.json = true
+
+	var item map[string]interface{}
+	tests.Check(c.Run(c.Cmd(), []string{"10SCREEN"}))
+	tests.Check(json.Unmarshal(r.Out.Bytes(), &item))
+	if item["code"] != "10SCREEN" {
+		t.Errorf("expected the item as json, got %q", r.Out.String())
+	}
+	r.ClearBuf()
+	c.item = "10SCREEN"
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	tests.Check(json.Unmarshal(r.Out.Bytes(), &item))
+	c.item = ""
+
+	r.ClearBuf()
+	var items []map[string]interface{}
+	c.search = []string{"tossed"}
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	tests.Check(json.Unmarshal(r.Out.Bytes(), &items))
+	if len(items) == 0 {
+		t.Errorf("expected search results as json, got %q", r.Out.String())
+	}
+	r.ClearBuf()
+	c.search = []string{"anchovies"}
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	r.Compare(t, "[]\n")
+	c.search = nil
+
+	c.toppings = true
+	if err := c.Run(c.Cmd(), []string{}); err == nil || err.Error() != "cannot use --toppings with --json" {
+		t.Errorf("expected an error for --toppings with --json, got %v", err)
+	}
 }
 
 func TestFindProduct(t *testing.T) {
+	srv := cmdtest.NewServer()
+	defer srv.Close()
 	r := cmdtest.NewRecorder()
 	defer r.CleanUp()
 	c := NewMenuCmd(r).(*menuCmd)
 
-	if err := r.DB().UpdateTS("menu", c); err != nil {
+	if err := r.DB().UpdateTS(c.CacheKey(), c); err != nil {
 		t.Error(err)
 	}
 	c.all = true
@@ -37,11 +128,114 @@ func TestFindProduct(t *testing.T) {
 	}
 	r.ClearBuf()
 	c.printToppings()
-	if len(r.Out.Bytes()) < 1000 {
-		t.Error("toppings menu seems too short")
+	for _, top := range []string{"Pepperoni", "Mushrooms", "Cheese"} {
+		if !r.Contains(top) {
+			t.Errorf("toppings menu should have %s, got %q", top, r.Out.String())
+		}
 	}
 }
 
+func TestMenuCategories(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	menu := &dawg.Menu{
+		Products: map[string]*dawg.Product{
+			"S_PIZZA": {ItemCommon: dawg.ItemCommon{Code: "S_PIZZA", Name: "Hand Tossed Pizza"}},
+			"S_COKE":  {ItemCommon: dawg.ItemCommon{Code: "S_COKE", Name: "Coke"}},
+		},
+	}
+	menu.Categorization.Food.Categories = []dawg.MenuCategory{
+		{Name: "Pizza", Code: "BuildYourOwn", Products: []string{"S_PIZZA"}},
+		{Name: "Specialty Pizzas", Code: "Specialty"},
+		{Name: "Drinks", Code: "Drinks", Products: []string{"S_COKE"}},
+	}
+	raw := &bytes.Buffer{}
+	tests.Check(gob.NewEncoder(raw).Encode(menu))
+	tests.Check(r.DB().Put(data.MenuKey("4336"), raw.Bytes()))
+	// a newer menu from another store is ignored when a store is saved
+	other := &dawg.Menu{}
+	other.Categorization.Food.Categories = []dawg.MenuCategory{{Name: "Other", Code: "Other"}}
+	tests.Check(data.SaveMenu(r.DB(), "4339", other))
+	tests.Check(data.SaveStore(r.DB(), "4336", cmdtest.TestAddress()))
+
+	// the cached menu is used so no requests are sent
+	c := NewMenuCmd(r).(*menuCmd)
+	c.showCategories = true
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	r.Compare(t, "pizza\nspecialty pizzas\ndrinks\n")
+	r.ClearBuf()
+
+	c.showCategories = false
+	for _, name := range []string{"DRINK", "rinks", "drinks"} {
+		c.category = name
+		tests.Check(c.Run(c.Cmd(), []string{}))
+		if !strings.Contains(r.Out.String(), "Coke") || strings.Contains(r.Out.String(), "Pizza") {
+			t.Errorf("wrong output for %q: %q", name, r.Out.String())
+		}
+		r.ClearBuf()
+	}
+	c.category = "pizza" // exact matches are not ambiguous
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	r.ClearBuf()
+
+	c.category = "pizz"
+	err := c.Run(c.Cmd(), []string{})
+	tests.Exp(err)
+	if err != nil && !strings.Contains(err.Error(), "pizza, specialty pizzas") {
+		t.Errorf("error should list the matching categories, got %q", err)
+	}
+	c.category = "sides"
+	tests.Exp(c.Run(c.Cmd(), []string{}))
+}
+
+func TestMenuDiff(t *testing.T) {
+	tests.InitHelpers(t)
+	srv := cmdtest.NewServer()
+	defer srv.Close()
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+
+	c := NewMenuCmd(r).(*menuCmd)
+	c.diff = true
+	tests.Exp(c.Run(c.Cmd(), []string{}), "should not diff without a cached menu")
+
+	old := &dawg.Menu{ID: cmdtest.StoreID, Variants: map[string]*dawg.Variant{
+		"10SCREEN": {ItemCommon: dawg.ItemCommon{Code: "10SCREEN", Name: "Small (10\") Hand Tossed Pizza"}, Price: "9.99"},
+		"12SCREEN": {ItemCommon: dawg.ItemCommon{Code: "12SCREEN", Name: "Medium (12\") Hand Tossed Pizza"}, Price: "10.99"},
+		"14SCREEN": {ItemCommon: dawg.ItemCommon{Code: "14SCREEN", Name: "Large (14\") Hand Tossed Pizza"}, Price: "13.990"},
+		"W08PHOTW": {ItemCommon: dawg.ItemCommon{Code: "W08PHOTW", Name: "Hot Wings"}, Price: "7.99"},
+	}}
+	tests.Check(data.SaveMenu(r.DataBase, cmdtest.StoreID, old))
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	r.Compare(t, `added:
+  2LCOKE        $3.19  2-Liter Coke
+removed:
+  W08PHOTW      $7.99  Hot Wings
+price changes:
+  12SCREEN     $10.99 -> $11.99   Medium (12") Hand Tossed Pizza
+`)
+	m, err := data.CachedMenu(r.DataBase, cmdtest.StoreID)
+	tests.Check(err)
+	if _, ok := m.Variants["W08PHOTW"]; !ok {
+		t.Error("the cached menu should not change without --update")
+	}
+
+	r.ClearBuf()
+	c.update = true
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	if !r.Contains("the cached menu for store 4336 was updated") {
+		t.Errorf("wrong output: %q", r.Out.String())
+	}
+	r.ClearBuf()
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	if !r.Contains("the menu has not changed") {
+		t.Errorf("the menu should be the same after --update, got %q", r.Out.String())
+	}
+	c.diff = false
+	tests.Exp(c.Run(c.Cmd(), []string{}), "--update should need --diff")
+}
+
 func TestStringStuff(t *testing.T) {
 	if strLen("123456") != 6 {
 		t.Error("wrong string len")
@@ -54,3 +248,40 @@ func TestStringStuff(t *testing.T) {
 		}
 	}
 }
+
+func TestSearchMenu(t *testing.T) {
+	menu := &dawg.Menu{
+		Products: map[string]*dawg.Product{
+			"S_PIZZA": {
+				ItemCommon:  dawg.ItemCommon{Code: "S_PIZZA", Name: "Pizza"},
+				Description: "Cheese and tomato sauce",
+			},
+			"S_WINGS": {
+				ItemCommon:  dawg.ItemCommon{Code: "S_WINGS", Name: "Chicken Wings"},
+				Description: "Spicy Buffalo",
+			},
+		},
+		Preconfigured: map[string]*dawg.PreConfiguredProduct{
+			"14SCVEGGIE": {
+				ItemCommon:  dawg.ItemCommon{Code: "14SCVEGGIE", Name: "Veggie Pizza"},
+				Description: "Mushrooms and onions",
+			},
+		},
+	}
+	tt := []struct {
+		terms []string
+		exp   []string
+	}{
+		{[]string{"pizza"}, []string{"14SCVEGGIE", "S_PIZZA"}},
+		{[]string{"CHEESE"}, []string{"S_PIZZA"}},
+		{[]string{"buffalo", " mushroom"}, []string{"14SCVEGGIE", "S_WINGS"}},
+		{[]string{"anchovies"}, nil},
+		{[]string{""}, nil},
+	}
+	for _, tc := range tt {
+		codes := searchMenu(menu, tc.terms)
+		if strings.Join(codes, ",") != strings.Join(tc.exp, ",") {
+			t.Errorf("search %v: got %v, want %v", tc.terms, codes, tc.exp)
+		}
+	}
+}
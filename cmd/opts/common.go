@@ -17,6 +17,52 @@ type CliFlags struct {
 	ClearCache bool
 	ResetMenu  bool
 	LogFile    string
+
+	// DryRun will stop any command from sending an order to dominos.
+	DryRun bool
+
+	// Yes skips any confirmation prompts. It does not
+	// override DryRun.
+	Yes bool
+
+	// Timeout is the time limit for all requests sent to dominos.
+	Timeout time.Duration
+
+	// Retries is the number of times a failed request for data (stores,
+	// menus) will be retried. RetryWait is the time to wait before the
+	// first retry, it doubles after each one.
+	Retries   int
+	RetryWait time.Duration
+
+	// Rate is the number of requests that can be sent to dominos
+	// every second, zero is unlimited.
+	Rate float64
+
+	// StoreID is the id of a store that should be used instead of
+	// finding the nearest store.
+	StoreID string
+
+	// Verbose is the log level for requests sent to dominos.
+	Verbose int
+
+	// Quiet hides the progress spinner that is shown while
+	// waiting for dominos.
+	Quiet bool
+
+	// DBPath is the path of the cache database, it overrides
+	// the db-path config field.
+	DBPath string
+
+	// ConfigFile is a config file used instead of the default.
+	ConfigFile string
+
+	// OutputFile is a file that all command output is written
+	// to instead of stdout.
+	OutputFile string
+
+	// Color is either auto, always, or never. Auto only colors
+	// the output when it is a terminal.
+	Color string
 }
 
 // Install the RootFlags
@@ -26,8 +72,21 @@ func (rf *CliFlags) Install(persistflags *pflag.FlagSet) {
 	persistflags.BoolVar(&rf.ResetMenu, "delete-menu", false, "delete the menu stored in cache")
 	persistflags.StringVar(&rf.LogFile, "log", "", "set a log file (found in ~/.config/apizza/logs)")
 
-	persistflags.StringVarP(&rf.Address, "address", "A", rf.Address, "an address name stored with 'apizza address --new'")
-	persistflags.StringVar(&rf.Service, "service", rf.Service, "select a Dominos service, either 'Delivery' or 'Carryout'")
+	persistflags.StringVar(&rf.DBPath, "db", "", "path of the cache database")
+	persistflags.StringVar(&rf.ConfigFile, "config", "", "use a different config file")
+	persistflags.StringVar(&rf.OutputFile, "output", "", "write the command output to a file instead of stdout")
+	persistflags.StringVarP(&rf.Address, "address", "A", rf.Address, "an address name stored with 'apizza config address add' or 'apizza address --new'")
+	persistflags.StringVar(&rf.Service, "service", rf.Service, "select a Dominos service, either 'delivery' or 'carryout'")
+	persistflags.BoolVar(&rf.DryRun, "dry-run", false, "go through the ordering process without sending the final order")
+	persistflags.BoolVarP(&rf.Yes, "yes", "y", false, "do not ask for confirmation (this will send orders without asking)")
+	persistflags.DurationVar(&rf.Timeout, "timeout", 0, "set a time limit for requests sent to dominos (ex. 30s)")
+	persistflags.CountVarP(&rf.Verbose, "verbose", "v", "log requests to stderr, use -vv to also log request bodies")
+	persistflags.BoolVar(&rf.Quiet, "quiet", false, "do not show a progress spinner while waiting for dominos")
+	persistflags.StringVar(&rf.StoreID, "store", "", "use the store with this id instead of finding the one nearest to the address")
+	persistflags.IntVar(&rf.Retries, "retries", 2, "number of times to retry a failed request for store or menu data")
+	persistflags.DurationVar(&rf.RetryWait, "retry-wait", 500*time.Millisecond, "time to wait before retrying a request (doubles after each retry)")
+	persistflags.Float64Var(&rf.Rate, "rate", 0, "limit the number of requests sent to dominos per second (0 for no limit)")
+	persistflags.StringVar(&rf.Color, "color", "auto", "color the output: auto, always, or never")
 }
 
 // ApizzaFlags that are not persistant.
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/client"
+	"github.com/harrybrwn/apizza/cmd/internal"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+type priceCmd struct {
+	cli.CliCommand
+	data.MenuCacher
+	client.StoreFinder
+
+	db         *cache.DataBase
+	getctx     func() context.Context
+	getservice func() string
+
+	raw bool
+}
+
+func (c *priceCmd) Run(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("no product codes given")
+	}
+	if err := c.db.UpdateTS(c.CacheKey(), c); err != nil {
+		return err
+	}
+	menu := c.Menu()
+	order := c.Store().NewOrder()
+	order.ServiceMethod = c.getservice()
+	if err := addTransientProducts(order, menu, args); err != nil {
+		return err
+	}
+
+	ctx := c.getctx()
+	if c.raw {
+		ctx = dawg.WithRawResponses(ctx, c.Output())
+	}
+	price, err := order.PriceContext(ctx)
+	if err != nil {
+		return internal.TimeoutErr(err)
+	}
+	if c.raw {
+		return nil
+	}
+	printPrice(c.Output(), order, menu, price)
+	return nil
+}
+
+// addTransientProducts adds one product to the order for every distinct code,
+// codes that are given more than once increase the product's quantity.
+func addTransientProducts(o *dawg.Order, menu *dawg.Menu, codes []string) error {
+	counts := make(map[string]int)
+	for _, code := range codes {
+		if counts[code] == 0 {
+			v, err := menu.GetVariant(code)
+			if err != nil {
+				return err
+			}
+			if err = o.AddProduct(v); err != nil {
+				return err
+			}
+		}
+		counts[code]++
+	}
+	for _, p := range o.Products {
+		p.Qty = counts[p.Code]
+	}
+	return nil
+}
+
+func printPrice(w io.Writer, o *dawg.Order, menu *dawg.Menu, total float64) {
+	fmt.Fprintf(w, "Store %s (%s)\n", o.StoreID, o.ServiceMethod)
+	for _, p := range o.Products {
+		line := "   ?"
+		if v, err := menu.GetVariant(p.Code); err == nil {
+			if each, err := strconv.ParseFloat(v.Price, 64); err == nil {
+				line = fmt.Sprintf("$%.2f", each*float64(p.Qty))
+			}
+		}
+		fmt.Fprintf(w, "  %-10s x%-3d %8s  %s\n", p.Code, p.Qty, line, p.Name)
+	}
+	fmt.Fprintf(w, "  total: $%.2f\n", total)
+}
+
+// NewPriceCmd creates the price command.
+func NewPriceCmd(b cli.Builder) cli.CliCommand {
+	c := &priceCmd{
+		db:     b.DB(),
+		getctx: b.Context,
+		getservice: func() string {
+			return cli.ServiceMethod(b)
+		},
+	}
+	if app, ok := b.(*App); ok {
+		c.StoreFinder = app
+	} else {
+		c.StoreFinder = client.NewStoreGetter(b)
+	}
+	c.CliCommand = b.Build("price <product code>...", "Price a set of products without saving an order.", c)
+	c.MenuCacher = data.NewMenuCacher(b.Context, menuUpdateTime, b.DB(), c.Store)
+	c.SetOutput(b.Output())
+	c.Cmd().Long = `The price command sends the products given to dominos to be priced
+and prints the total. The order is never saved so the cart is left untouched.
+
+Give a product code more than once to order more than one of it.
+
+Use --raw to print the pricing response exactly as dominos sent it.`
+	c.Flags().BoolVar(&c.raw, "raw", false, "print the unparsed response sent by dominos")
+	return c
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestPriceProducts(t *testing.T) {
+	tests.InitHelpers(t)
+	menu := &dawg.Menu{
+		Products: map[string]*dawg.Product{"S_PIZZA": {}, "S_WINGS": {}},
+		Variants: map[string]*dawg.Variant{
+			"12SCREEN": {ItemCommon: dawg.ItemCommon{Code: "12SCREEN", Name: "Medium Pizza"}, ProductCode: "S_PIZZA", Price: "11.99"},
+			"W08PHOTW": {ItemCommon: dawg.ItemCommon{Code: "W08PHOTW", Name: "Hot Wings"}, ProductCode: "S_WINGS", Price: "7.99"},
+		},
+	}
+	o := cmdtest.NewTestOrder()
+	tests.Exp(addTransientProducts(o, menu, []string{"12SCREEN", "nope"}))
+
+	o = cmdtest.NewTestOrder()
+	tests.Check(addTransientProducts(o, menu, []string{"12SCREEN", "W08PHOTW", "12SCREEN"}))
+	if len(o.Products) != 2 {
+		t.Fatalf("expected 2 products, got %d", len(o.Products))
+	}
+	if o.Products[0].Qty != 2 || o.Products[1].Qty != 1 {
+		t.Error("repeated codes should increase the quantity")
+	}
+
+	buf := &bytes.Buffer{}
+	o.StoreID = "4336"
+	o.ServiceMethod = dawg.Carryout
+	printPrice(buf, o, menu, 34.12)
+	tests.Compare(t, buf.String(), `Store 4336 (Carryout)
+  12SCREEN   x2     $23.98  Medium Pizza
+  W08PHOTW   x1      $7.99  Hot Wings
+  total: $34.12
+`)
+}
+
+func TestPriceRun(t *testing.T) {
+	tests.InitHelpers(t)
+	srv := cmdtest.NewServer()
+	defer srv.Close()
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewPriceCmd(r).(*priceCmd)
+
+	tests.Exp(c.Run(c.Cmd(), []string{}))
+	// the default price fixture is for one 14SCREEN so this
+	// order gets a price that matches its items plus tax
+	srv.SetResponse("/power/price-order", http.StatusOK,
+		`{"Status":0,"Order":{"Status":0,"OrderID":"mock-order-id","Amounts":{"Menu":31.17,"Tax":1.87,"Customer":33.04}}}`)
+	tests.Check(c.Run(c.Cmd(), []string{"14SCREEN", "2LCOKE", "14SCREEN"}))
+	r.Compare(t, `Store 4336 (Carryout)
+  14SCREEN   x2     $27.98  Large (14") Hand Tossed Pizza
+  2LCOKE     x1      $3.19  2-Liter Coke
+  total: $33.04
+`)
+	if body := srv.Body("/power/price-order"); !bytes.Contains(body, []byte(`"Code":"2LCOKE"`)) || !bytes.Contains(body, []byte(`"Qty":2`)) {
+		t.Errorf("the products should be sent to be priced, got %s", body)
+	}
+	r.ClearBuf()
+	srv.SetResponse("/power/price-order", http.StatusOK, cmdtest.PriceOrderJSON)
+	c.raw = true
+	tests.Check(c.Run(c.Cmd(), []string{"14SCREEN"}))
+	r.Compare(t, cmdtest.PriceOrderJSON+"\n")
+}
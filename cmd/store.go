@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/client"
+	"github.com/harrybrwn/apizza/cmd/internal"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+type storeCmd struct {
+	cli.CliCommand
+	db *cache.DataBase
+
+	getaddr    func() dawg.Address
+	getservice func() string
+	getctx     func() context.Context
+	geocoder   func() dawg.Geocoder
+	progress   func() *cli.Progress
+
+	nearest bool
+	current bool
+	raw     bool
+	top     int
+	workers int
+}
+
+func (c *storeCmd) Run(cmd *cobra.Command, args []string) error {
+	if c.current {
+		return c.printCurrent()
+	}
+	addr := c.getaddr()
+	if obj.AddrIsEmpty(addr) {
+		return internal.ErrNoAddress
+	}
+	located := client.GeocodeAddress(c.getctx(), c.geocoder(), addr)
+	ctx, workers := c.getctx(), c.workers
+	if c.raw {
+		// one worker keeps the responses in the same order as the stores
+		ctx, workers = dawg.WithRawResponses(ctx, c.Output()), 1
+	}
+	progress := c.progress()
+	progress.Start("finding stores")
+	stores, err := dawg.GetNearestStoresContext(ctx, located, c.getservice(), c.top, workers)
+	progress.Stop()
+	if _, ok := err.(dawg.StoreErrors); ok && len(stores) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if err != nil {
+		return internal.TimeoutErr(err)
+	}
+	if c.raw {
+		return nil
+	}
+	if len(stores) == 0 {
+		return errors.New("no stores found near this address")
+	}
+
+	if c.nearest {
+		store := stores[0]
+		if err = data.SaveStore(c.db, store.ID, addr); err != nil {
+			return err
+		}
+		c.Printf("Selected the nearest store:\n")
+		printStore(c.Output(), store)
+		return nil
+	}
+	for i, store := range stores {
+		c.Printf("%d. ", i+1)
+		printStore(c.Output(), store)
+	}
+	return nil
+}
+
+func (c *storeCmd) printCurrent() error {
+	id, addr, err := data.SavedStore(c.db)
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		c.Printf("no store is selected (see 'apizza store --nearest')\n")
+		return nil
+	}
+	c.Printf("Store %s\n", id)
+	if !obj.AddrIsEmpty(addr) {
+		c.Printf("   selected for %s\n", obj.AddressFmtIndent(addr, 16))
+		if current := c.getaddr(); !obj.AddrIsEmpty(current) && *addr != *obj.FromAddress(current) {
+			c.Printf("   the address has changed so a new store will be found\n")
+		}
+	}
+	return nil
+}
+
+func printStore(w io.Writer, store *dawg.Store) {
+	if d := store.Distance(); d < 0 {
+		fmt.Fprintf(w, "Store %s (distance unknown)\n", store.ID)
+	} else {
+		fmt.Fprintf(w, "Store %s (%.2f mi)\n", store.ID, d)
+	}
+	addr := strings.TrimSpace(store.Address)
+	fmt.Fprintf(w, "   %s\n", strings.Replace(addr, "\n", "\n   ", -1))
+	if store.Phone != "" {
+		fmt.Fprintf(w, "   phone: %s\n", store.Phone)
+	}
+}
+
+// NewStoreCmd creates the store command.
+func NewStoreCmd(b cli.Builder) cli.CliCommand {
+	c := &storeCmd{
+		db:      b.DB(),
+		getaddr: b.Address,
+		getservice: func() string {
+			return cli.ServiceMethod(b)
+		},
+		getctx:   b.Context,
+		geocoder: b.Geocoder,
+		progress: b.Progress,
+		nearest:  false,
+	}
+	c.CliCommand = b.Build("store", "List the dominos stores near your address.", c)
+	c.SetOutput(b.Output())
+	c.Cmd().Long = `The store command lists the dominos stores near the current address
+sorted by their distance from the address. Stores that do not have any
+coordinates are listed last.
+
+Use the --nearest flag to select the closest store and cache its id.
+The store that was last found is used by the other commands until the address
+changes or a store is given with the global --store flag. Use --current to
+show the selected store.
+
+Use --top to only get the nearest few stores. The details of each store are
+fetched concurrently by a pool of --workers workers and the whole search is
+limited by the global --timeout flag. If some of the stores cannot be found,
+a warning is printed and the rest of the stores are still listed.
+
+Use --raw to print the responses from dominos instead of the list of stores.
+The store search is printed first and then the details of each store.`
+	c.Flags().BoolVar(&c.nearest, "nearest", c.nearest, "select the closest store and cache its id")
+	c.Flags().BoolVar(&c.current, "current", c.current, "show the store that is currently selected")
+	c.Flags().BoolVar(&c.raw, "raw", c.raw, "print the unparsed responses sent by dominos")
+	c.Flags().IntVar(&c.top, "top", 0, "only get the n nearest stores (0 for all)")
+	c.Flags().IntVar(&c.workers, "workers", 4, "the number of stores to get at the same time")
+	return c
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestPrintStore(t *testing.T) {
+	buf := &bytes.Buffer{}
+	store := &dawg.Store{
+		ID:          "4336",
+		Phone:       "202-555-0100",
+		Address:     "1300 L St NW\nWashington, DC 20005\n",
+		StoreCoords: map[string]string{"StoreLatitude": "38.9", "StoreLongitude": "-77.03"},
+		MinDistance: 0.7,
+	}
+	printStore(buf, store)
+	tests.Compare(t, buf.String(), `Store 4336 (0.70 mi)
+   1300 L St NW
+   Washington, DC 20005
+   phone: 202-555-0100
+`)
+	buf.Reset()
+	store.StoreCoords = nil
+	store.Phone = ""
+	printStore(buf, store)
+	tests.Compare(t, buf.String(), `Store 4336 (distance unknown)
+   1300 L St NW
+   Washington, DC 20005
+`)
+}
+
+func TestStoreCurrent(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewStoreCmd(r).(*storeCmd)
+	c.current = true
+
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	r.Compare(t, "no store is selected (see 'apizza store --nearest')\n")
+	r.ClearBuf()
+
+	tests.Check(data.SaveStore(r.DB(), "4336", r.Address()))
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	r.Compare(t, `Store 4336
+   selected for 1600 Pennsylvania Ave NW
+                Washington, DC 20500
+`)
+	r.ClearBuf()
+
+	c.getaddr = func() dawg.Address {
+		return &obj.Address{Street: "1 Main St", CityName: "Washington", State: "DC", Zipcode: "20500"}
+	}
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	if !r.Contains("the address has changed") {
+		t.Errorf("should say that the address changed, got %q", r.Out.String())
+	}
+}
+
+func TestStoreRun(t *testing.T) {
+	tests.InitHelpers(t)
+	srv := cmdtest.NewServer()
+	defer srv.Close()
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewStoreCmd(r).(*storeCmd)
+
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	r.Compare(t, `1. Store 4336 (0.70 mi)
+   1300 L St NW
+   Washington, DC 20005
+   phone: 202-555-0100
+`)
+	r.ClearBuf()
+
+	c.nearest = true
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	id, _, err := data.SavedStore(r.DB())
+	tests.Check(err)
+	tests.StrEq(id, cmdtest.StoreID, "the nearest store should be saved")
+	r.ClearBuf()
+
+	c.nearest, c.raw = false, true
+	tests.Check(c.Run(c.Cmd(), []string{}))
+	r.Compare(t, cmdtest.StoreLocatorJSON+"\n"+cmdtest.StoreProfileJSON+"\n")
+}
@@ -2,6 +2,7 @@ package dawg
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -169,10 +170,48 @@ func newAuthRequest(u *url.URL, vals url.Values) *http.Request {
 type client struct {
 	*http.Client
 	host string
+
+	// scheme is the url scheme used for requests, https if it is empty
+	scheme string
+}
+
+func (c *client) urlScheme() string {
+	if c.scheme == "" {
+		return "https"
+	}
+	return c.scheme
+}
+
+// SetBaseURL changes the url that requests for stores, menus, and orders are
+// sent to. It is meant for testing against a fake dominos server, an empty
+// url will go back to sending requests to dominos. The url cannot have a path
+// because requests always use the dominos paths. SetBaseURL should not be
+// called while requests are being sent.
+func SetBaseURL(base string) error {
+	if base == "" {
+		orderClient.host, orderClient.scheme = orderHost, ""
+		return nil
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return err
+	}
+	if u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") || (u.Path != "" && u.Path != "/") {
+		return fmt.Errorf("bad base url '%s', use <http or https>://<host>", base)
+	}
+	orderClient.host, orderClient.scheme = u.Host, u.Scheme
+	return nil
 }
 
 func (c *client) do(req *http.Request) ([]byte, error) {
 	var buf bytes.Buffer
+	if err := rateLimitFrom(req.Context()).Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	logger := loggerFrom(req.Context())
+	if logger != nil {
+		logger.LogRequest(req, readBody(req))
+	}
 	resp, err := c.Do(req)
 	if err != nil {
 		return nil, err
@@ -180,16 +219,28 @@ func (c *client) do(req *http.Request) ([]byte, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("dawg.client.do: bad status code %d", resp.StatusCode)
+		if logger != nil {
+			logger.LogResponse(resp, nil)
+		}
+		return nil, &statusError{code: resp.StatusCode}
 	}
 	_, err = buf.ReadFrom(resp.Body)
+	if logger != nil {
+		logger.LogResponse(resp, buf.Bytes())
+	}
 	if bytes.HasPrefix(bytes.ToLower(buf.Bytes()[:15]), []byte("<!doctype html>")) {
 		return nil, errpair(err, errors.New("got html response"))
 	}
+	if err == nil {
+		err = writeRaw(req.Context(), buf.Bytes())
+	}
 	return buf.Bytes(), err
 }
 
 func (c *client) dojson(v interface{}, r *http.Request) (err error) {
+	if err = rateLimitFrom(r.Context()).Wait(r.Context()); err != nil {
+		return err
+	}
 	resp, err := c.Do(r)
 	if err != nil {
 		return err
@@ -203,25 +254,29 @@ func (c *client) dojson(v interface{}, r *http.Request) (err error) {
 	return json.NewDecoder(resp.Body).Decode(v)
 }
 
-func (c *client) get(path string, params URLParam) ([]byte, error) {
+func (c *client) get(ctx context.Context, path string, params URLParam) ([]byte, error) {
 	if params == nil {
 		params = &Params{}
 	}
-	return c.do(&http.Request{
+	req := &http.Request{
 		Method: "GET",
 		Host:   c.host,
 		Proto:  "HTTP/1.1",
 		Header: make(http.Header),
 		URL: &url.URL{
-			Scheme:   "https",
+			Scheme:   c.urlScheme(),
 			Host:     c.host,
 			Path:     path,
 			RawQuery: params.Encode(),
 		},
+	}
+	req = req.WithContext(ctx)
+	return withRetries(ctx, func() ([]byte, error) {
+		return c.do(req)
 	})
 }
 
-func (c *client) post(path string, params URLParam, r io.Reader) ([]byte, error) {
+func (c *client) post(ctx context.Context, path string, params URLParam, r io.Reader) ([]byte, error) {
 	if params == nil {
 		params = &Params{}
 	}
@@ -229,19 +284,20 @@ func (c *client) post(path string, params URLParam, r io.Reader) ([]byte, error)
 	if !ok && r != nil {
 		rc = ioutil.NopCloser(r)
 	}
-	return c.do(&http.Request{
+	req := &http.Request{
 		Method: "POST",
 		Host:   c.host,
 		Proto:  "HTTP/1.1",
 		Header: make(http.Header),
 		Body:   rc,
 		URL: &url.URL{
-			Scheme:   "https",
+			Scheme:   c.urlScheme(),
 			Host:     c.host,
 			Path:     path,
 			RawQuery: params.Encode(),
 		},
-	})
+	}
+	return c.do(req.WithContext(ctx))
 }
 
 func unmarshalToken(r io.ReadCloser, t *token) error {
@@ -2,11 +2,14 @@ package dawg
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/rand"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -77,18 +80,18 @@ func TestParseAddressTable(t *testing.T) {
 func TestNetworking_Err(t *testing.T) {
 	tests.InitHelpers(t)
 	defer swapclient(3)()
-	_, err := orderClient.get("/", nil)
+	_, err := orderClient.get(context.Background(), "/", nil)
 	tests.Exp(err)
-	_, err = orderClient.get("/invalid path", nil)
+	_, err = orderClient.get(context.Background(), "/invalid path", nil)
 	tests.Exp(err)
-	b, err := orderClient.post("/invalid path", nil, bytes.NewReader(make([]byte, 1)))
+	b, err := orderClient.post(context.Background(), "/invalid path", nil, bytes.NewReader(make([]byte, 1)))
 	tests.Exp(err)
 	if len(b) != 0 {
 		t.Error("expected zero length response")
 	}
-	_, err = orderClient.post("invalid path", nil, bytes.NewReader(nil))
+	_, err = orderClient.post(context.Background(), "invalid path", nil, bytes.NewReader(nil))
 	tests.Exp(err)
-	_, err = orderClient.post("/power/price-order", nil, bytes.NewReader([]byte{}))
+	_, err = orderClient.post(context.Background(), "/power/price-order", nil, bytes.NewReader([]byte{}))
 	tests.Exp(err)
 	cli := &client{
 		Client: &http.Client{
@@ -100,12 +103,12 @@ func TestNetworking_Err(t *testing.T) {
 			Timeout: time.Second,
 		},
 	}
-	resp, err := cli.get("/power/store/4336/profile", nil)
+	resp, err := cli.get(context.Background(), "/power/store/4336/profile", nil)
 	tests.Exp(err)
 	if resp != nil {
 		t.Error("should not have gotten any response data")
 	}
-	b, err = cli.post("/invalid path", nil, bytes.NewReader(make([]byte, 1)))
+	b, err = cli.post(context.Background(), "/invalid path", nil, bytes.NewReader(make([]byte, 1)))
 	tests.Exp(err)
 	if b != nil {
 		t.Error("expected zero length response")
@@ -144,7 +147,7 @@ func TestDominosErrors(t *testing.T) {
 		OrderID: "",
 		Address: testAddress(),
 	}
-	resp, err := orderClient.post("/power/price-order", nil, order.raw())
+	resp, err := orderClient.post(context.Background(), "/power/price-order", nil, order.raw())
 	if err != nil {
 		t.Error(err)
 	}
@@ -206,6 +209,40 @@ func TestDominosErrorFailure(t *testing.T) {
 	}
 }
 
+func TestDominosErrorIs(t *testing.T) {
+	tests.InitHelpers(t)
+	for _, tc := range []struct {
+		resp string
+		exp  error
+	}{
+		{`{"Status":-1,"Order":{"Status":-1,"StatusItems":[{"Code":"StoreClosed"}]}}`, ErrStoreClosed},
+		{`{"Status":-1,"StatusItems":[{"Code":"LocationNotFound"}]}`, ErrInvalidAddress},
+		{`{"Status":-1,"Order":{"Status":-1,"StatusItems":[{"Code":"Warning"},{"Code":"CardDeclined"}]}}`, ErrPaymentDeclined},
+	} {
+		err := dominosErr([]byte(tc.resp))
+		if !errors.Is(err, tc.exp) {
+			t.Errorf("%q should match %v", tc.resp, tc.exp)
+		}
+		wrapped := fmt.Errorf("wrapped: %w", err)
+		if !errors.Is(wrapped, tc.exp) {
+			t.Errorf("wrapped error should still match %v", tc.exp)
+		}
+		var e *DominosError
+		if !errors.As(wrapped, &e) || !IsFailure(wrapped) {
+			t.Error("should be able to get the dominos error from a wrapped error")
+		}
+		for _, other := range []error{ErrStoreClosed, ErrInvalidAddress, ErrPaymentDeclined} {
+			if other != tc.exp && errors.Is(err, other) {
+				t.Errorf("%q should not match %v", tc.resp, other)
+			}
+		}
+	}
+	err := dominosErr([]byte(`{"Status":-1,"Order":{"Status":-1,"StatusItems":[{"Code":"PosOrderIncomplete"}]}}`))
+	if errors.Is(err, ErrStoreClosed) || errors.Is(err, ErrInvalidAddress) || errors.Is(err, ErrPaymentDeclined) {
+		t.Error("unknown codes should not match any of the errors")
+	}
+}
+
 func TestErrPair(t *testing.T) {
 	tt := []struct {
 		err error
@@ -261,3 +298,164 @@ func testingMenu() *Menu {
 	}
 	return testMenu
 }
+
+// testServerClient creates a client that will send all of its requests
+// to a local tls server.
+func testServerClient(h http.Handler) (*client, func()) {
+	srv := httptest.NewTLSServer(h)
+	return &client{host: srv.Listener.Addr().String(), Client: srv.Client()}, srv.Close
+}
+
+func TestClientContext(t *testing.T) {
+	cli, done := testServerClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := cli.get(ctx, "/power/store-locator", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline exceeded error, got %v", err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = cli.post(ctx, "/power/price-order", nil, bytes.NewReader(nil))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a canceled error, got %v", err)
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	var calls int
+	cli, done := testServerClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"Status":0}`))
+	}))
+	defer done()
+
+	ctx := WithRetry(context.Background(), 3, time.Millisecond)
+	b, err := cli.get(ctx, "/power/store-locator", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if string(b) != `{"Status":0}` || calls != 3 {
+		t.Errorf("expected a response after 3 attempts, got %d attempts", calls)
+	}
+
+	calls = 0
+	_, err = cli.get(WithRetry(context.Background(), 1, time.Millisecond), "/", nil)
+	if err == nil || calls != 2 {
+		t.Errorf("expected an error after 2 attempts, got %d attempts", calls)
+	}
+	calls = 0
+	_, err = cli.get(context.Background(), "/", nil)
+	if err == nil || calls != 1 {
+		t.Error("should not retry without a retry policy")
+	}
+	calls = 0
+	_, err = cli.post(ctx, "/power/place-order", nil, bytes.NewReader(nil))
+	if err == nil || calls != 1 {
+		t.Error("post requests should never be retried")
+	}
+
+	calls = 0
+	cli, done = testServerClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer done()
+	if _, err = cli.get(ctx, "/", nil); err == nil || calls != 1 {
+		t.Error("client errors should not be retried")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	if NewRateLimiter(0) != nil || NewRateLimiter(-1) != nil {
+		t.Error("a rate that is not positive should be unlimited")
+	}
+	var unlimited *RateLimiter
+	if err := unlimited.Wait(context.Background()); err != nil {
+		t.Error(err)
+	}
+
+	var calls int
+	cli, done := testServerClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"Status":0}`))
+	}))
+	defer done()
+
+	ctx := WithRateLimit(context.Background(), NewRateLimiter(100))
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := cli.get(ctx, "/", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("4 requests at 100/s should take about 30ms, took %v", elapsed)
+	}
+	if calls != 4 {
+		t.Errorf("expected 4 requests, got %d", calls)
+	}
+
+	// the first request uses up the bucket so the next one
+	// would have to wait past the deadline
+	calls = 0
+	ctx = WithRateLimit(context.Background(), NewRateLimiter(0.1))
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := cli.get(ctx, "/", nil); err != nil {
+		t.Fatal(err)
+	}
+	start = time.Now()
+	_, err := cli.get(ctx, "/", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got %v", err)
+	}
+	if time.Since(start) > time.Second || calls != 1 {
+		t.Error("a request that cannot be sent before the deadline should not wait")
+	}
+}
+
+func TestSetBaseURL(t *testing.T) {
+	defer SetBaseURL("")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/power/store/4336/profile" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"Status":0,"StoreID":"4336","IsOnlineNow":true}`))
+	}))
+	defer srv.Close()
+
+	for _, bad := range []string{"localhost:8080", "ftp://localhost", "http://", "http://localhost:8080/api"} {
+		if err := SetBaseURL(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+	if err := SetBaseURL(srv.URL + "/"); err != nil {
+		t.Errorf("a trailing slash should be allowed: %v", err)
+	}
+	if err := SetBaseURL(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewStoreContext(context.Background(), "4336", Carryout, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.ID != "4336" || !store.IsOnlineNow {
+		t.Errorf("wrong store from the test server: %+v", store)
+	}
+	if err = SetBaseURL(""); err != nil {
+		t.Fatal(err)
+	}
+	if orderClient.host != orderHost || orderClient.urlScheme() != "https" {
+		t.Error("an empty url should go back to dominos")
+	}
+}
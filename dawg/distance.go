@@ -0,0 +1,137 @@
+package dawg
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// earthRadius is the mean radius of the earth in miles.
+const earthRadius = 3958.8
+
+// Coordinates is a latitude and longitude pair measured in degrees.
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Distance returns the distance in miles between two sets of coordinates
+// using the haversine formula.
+func (c Coordinates) Distance(to Coordinates) float64 {
+	lat1, lat2 := radians(c.Latitude), radians(to.Latitude)
+	dlat := lat2 - lat1
+	dlong := radians(to.Longitude - c.Longitude)
+
+	a := math.Pow(math.Sin(dlat/2), 2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dlong/2), 2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// Locator is an Address that knows its own coordinates. If the address
+// given when finding stores implements Locator then store distances will be
+// computed from the address coordinates.
+type Locator interface {
+	Coordinates() (Coordinates, bool)
+}
+
+// Geocoder finds the coordinates of an address. A Geocoder can be given to
+// Geocode so that the distances to stores are measured from the address.
+type Geocoder interface {
+	Geocode(ctx context.Context, addr Address) (Coordinates, error)
+}
+
+// ErrNoCoordinates is returned by a Geocoder when it cannot find the
+// coordinates of an address.
+var ErrNoCoordinates = errors.New("could not find the coordinates of the address")
+
+// DominosGeocoder is the default Geocoder. Dominos does not give the
+// coordinates of an address, only the distance from the address to each
+// store, so it will only find coordinates for an address that implements
+// Locator. For any other address the distances given by the dominos store
+// locator are used.
+type DominosGeocoder struct{}
+
+// Geocode returns the address coordinates if the address is a Locator and
+// ErrNoCoordinates if it is not.
+func (DominosGeocoder) Geocode(ctx context.Context, addr Address) (Coordinates, error) {
+	if l, ok := addr.(Locator); ok {
+		if c, ok := l.Coordinates(); ok {
+			return c, nil
+		}
+	}
+	return Coordinates{}, ErrNoCoordinates
+}
+
+// Geocode uses a Geocoder to find the coordinates of an address and returns
+// an address that implements Locator. The address is returned as is if the
+// geocoder gives ErrNoCoordinates.
+func Geocode(ctx context.Context, g Geocoder, addr Address) (Address, error) {
+	c, err := g.Geocode(ctx, addr)
+	if err == ErrNoCoordinates {
+		return addr, nil
+	} else if err != nil {
+		return addr, err
+	}
+	return &locatedAddress{Address: addr, coords: c}, nil
+}
+
+type locatedAddress struct {
+	Address
+	coords Coordinates
+}
+
+func (l *locatedAddress) Coordinates() (Coordinates, bool) {
+	return l.coords, true
+}
+
+// Coordinates returns the latitude and longitude of the store. The boolean
+// return value will be false if the store has no coordinates.
+func (s *Store) Coordinates() (Coordinates, bool) {
+	lat, err := strconv.ParseFloat(s.StoreCoords["StoreLatitude"], 64)
+	if err != nil {
+		return Coordinates{}, false
+	}
+	long, err := strconv.ParseFloat(s.StoreCoords["StoreLongitude"], 64)
+	if err != nil {
+		return Coordinates{}, false
+	}
+	return Coordinates{Latitude: lat, Longitude: long}, true
+}
+
+// Distance returns the distance in miles between the store and the address
+// used to find the store. If the address does not implement Locator, the
+// distance reported by the dominos store locator is used. Distance will
+// return -1 if the store has no coordinates.
+func (s *Store) Distance() float64 {
+	to, ok := s.Coordinates()
+	if !ok {
+		return -1
+	}
+	if l, ok := s.userAddress.(Locator); ok {
+		if from, ok := l.Coordinates(); ok {
+			return from.Distance(to)
+		}
+	}
+	return s.MinDistance
+}
+
+// SortByDistance sorts a list of stores in ascending order of their
+// distance from the user's address. Any stores without coordinates
+// will be moved to the end of the list.
+func SortByDistance(stores []*Store) {
+	sort.SliceStable(stores, func(i, j int) bool {
+		di, dj := stores[i].Distance(), stores[j].Distance()
+		if di < 0 {
+			return false
+		} else if dj < 0 {
+			return true
+		}
+		return di < dj
+	})
+}
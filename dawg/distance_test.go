@@ -0,0 +1,118 @@
+package dawg
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+type locatorAddr struct {
+	*StreetAddr
+	coords Coordinates
+}
+
+func (l *locatorAddr) Coordinates() (Coordinates, bool) { return l.coords, true }
+
+func TestCoordinatesDistance(t *testing.T) {
+	// The White House to the Washington Monument is about half a mile
+	from := Coordinates{Latitude: 38.8977, Longitude: -77.0365}
+	to := Coordinates{Latitude: 38.8895, Longitude: -77.0353}
+	d := from.Distance(to)
+	if math.Abs(d-0.57) > 0.05 {
+		t.Errorf("wrong distance: got %f", d)
+	}
+	if from.Distance(from) != 0 {
+		t.Error("distance to the same point should be zero")
+	}
+}
+
+func TestSortByDistance(t *testing.T) {
+	addr := &locatorAddr{
+		StreetAddr: testAddress(),
+		coords:     Coordinates{Latitude: 38.8977, Longitude: -77.0365},
+	}
+	coords := func(lat, long string) map[string]string {
+		return map[string]string{"StoreLatitude": lat, "StoreLongitude": long}
+	}
+	stores := []*Store{
+		{ID: "far", StoreCoords: coords("39.2904", "-76.6122"), userAddress: addr},
+		{ID: "none", StoreCoords: nil, userAddress: addr},
+		{ID: "near", StoreCoords: coords("38.9", "-77.04"), userAddress: addr},
+		{ID: "bad", StoreCoords: coords("", "-77.04"), userAddress: addr},
+		{ID: "middle", StoreCoords: coords("38.95", "-77.1"), userAddress: addr},
+	}
+	SortByDistance(stores)
+	exp := []string{"near", "middle", "far", "none", "bad"}
+	for i, id := range exp {
+		if stores[i].ID != id {
+			t.Errorf("store %d: got %s, want %s", i, stores[i].ID, id)
+		}
+	}
+	if stores[3].Distance() != -1 {
+		t.Error("a store without coordinates should have a distance of -1")
+	}
+
+	// without address coordinates the store locator distance is used
+	stores = []*Store{
+		{ID: "2", StoreCoords: coords("1", "1"), MinDistance: 2.5},
+		{ID: "1", StoreCoords: coords("1", "1"), MinDistance: 0.5},
+	}
+	SortByDistance(stores)
+	if stores[0].ID != "1" || stores[0].Distance() != 0.5 {
+		t.Error("stores should be sorted by the store locator distance")
+	}
+}
+
+type testGeocoder struct {
+	coords Coordinates
+	err    error
+}
+
+func (g *testGeocoder) Geocode(ctx context.Context, addr Address) (Coordinates, error) {
+	return g.coords, g.err
+}
+
+func TestGeocode(t *testing.T) {
+	ctx := context.Background()
+	white := Coordinates{Latitude: 38.8977, Longitude: -77.0365}
+	addr, err := Geocode(ctx, &testGeocoder{coords: white}, testAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, ok := addr.(Locator)
+	if !ok {
+		t.Fatal("a geocoded address should be a Locator")
+	}
+	if c, _ := l.Coordinates(); c != white {
+		t.Error("wrong address coordinates")
+	}
+	if addr.Zip() != testAddress().Zip() {
+		t.Error("the geocoded address should keep the original address")
+	}
+	store := &Store{StoreCoords: map[string]string{"StoreLatitude": "38.8895", "StoreLongitude": "-77.0353"}, MinDistance: 10, userAddress: addr}
+	if d := store.Distance(); math.Abs(d-0.57) > 0.05 {
+		t.Errorf("store distance should come from the geocoder, got %f", d)
+	}
+
+	plain := testAddress()
+	addr, err = Geocode(ctx, DominosGeocoder{}, plain)
+	if err != nil {
+		t.Error(err)
+	}
+	if addr != Address(plain) {
+		t.Error("the default geocoder should not change an address without coordinates")
+	}
+	located := &locatorAddr{StreetAddr: plain, coords: white}
+	if c, err := (DominosGeocoder{}).Geocode(ctx, located); err != nil || c != white {
+		t.Error("the default geocoder should use the coordinates of a Locator")
+	}
+
+	e := errors.New("geocoder is down")
+	if addr, err = Geocode(ctx, &testGeocoder{err: e}, plain); err != e {
+		t.Error("expected the geocoder error, got:", err)
+	}
+	if addr != Address(plain) {
+		t.Error("should return the address when the geocoder fails")
+	}
+}
@@ -28,6 +28,41 @@ var (
 	ErrNoUserService = errors.New("UserProfile has no service method (use user.SetServiceMethod)")
 )
 
+// These errors are matched by the errors that dominos sends back, use
+// errors.Is to check for them and errors.As to get the *DominosError.
+var (
+	// ErrStoreClosed is matched when the store is not taking orders.
+	ErrStoreClosed = errors.New("the store is closed")
+
+	// ErrInvalidAddress is matched when dominos cannot find an
+	// address or the address is missing information.
+	ErrInvalidAddress = errors.New("the address is invalid")
+
+	// ErrPaymentDeclined is matched when a payment is rejected.
+	ErrPaymentDeclined = errors.New("the payment was declined")
+)
+
+// statusCodes maps the codes of the status items sent by dominos to the
+// errors that they match.
+var statusCodes = map[string]error{
+	"StoreClosed":               ErrStoreClosed,
+	"StoreIsClosed":             ErrStoreClosed,
+	"StoreClosedForFutureOrder": ErrStoreClosed,
+
+	"AddressInvalid":    ErrInvalidAddress,
+	"InvalidAddress":    ErrInvalidAddress,
+	"LocationNotFound":  ErrInvalidAddress,
+	"StreetRequired":    ErrInvalidAddress,
+	"PostalCodeInvalid": ErrInvalidAddress,
+
+	"PaymentDeclined":     ErrPaymentDeclined,
+	"CardDeclined":        ErrPaymentDeclined,
+	"CreditCardDeclined":  ErrPaymentDeclined,
+	"CardNumberInvalid":   ErrPaymentDeclined,
+	"CardExpired":         ErrPaymentDeclined,
+	"InvalidSecurityCode": ErrPaymentDeclined,
+}
+
 var (
 	// Warnings is a package switch for turning warnings on or off
 	Warnings = false
@@ -81,6 +116,20 @@ func (err *DominosError) init(jsonData []byte) error {
 	return mapstructure.Decode(err.fullErr, err)
 }
 
+// Is returns true if one of the status codes sent by dominos matches the
+// target. It is used by errors.Is so that callers can check for
+// ErrStoreClosed, ErrInvalidAddress, and ErrPaymentDeclined.
+func (err *DominosError) Is(target error) bool {
+	for _, items := range [][]statusItem{err.StatusItems, err.Order.StatusItems} {
+		for _, item := range items {
+			if e, ok := statusCodes[item.Code]; ok && e == target {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (err *DominosError) Error() string {
 	var (
 		buf      = new(bytes.Buffer)
@@ -151,8 +200,8 @@ func IsOk(err error) bool {
 }
 
 func isDominosErr(err error) (*DominosError, bool) {
-	e, ok := err.(*DominosError)
-	if !ok {
+	var e *DominosError
+	if !errors.As(err, &e) {
 		return nil, false
 	}
 	return e, true
@@ -0,0 +1,119 @@
+package dawg
+
+import "time"
+
+// FutureOrders is the part of a store's profile that describes how it takes
+// scheduled orders (see Order.ScheduleFor).
+type FutureOrders struct {
+	// TimeZoneMinutes is the store's offset from UTC in minutes and
+	// TimeZoneCode is the name of its time zone, ex. "GMT-04:00".
+	TimeZoneMinutes int
+	TimeZoneCode    string
+
+	// DelayInHours is how many hours ahead of time an order has to be
+	// scheduled and MaxDays is how many days ahead it can be scheduled.
+	// They are zero if the store does not give a limit.
+	DelayInHours int `json:"FutureOrderDelayInHours"`
+	MaxDays      int `json:"FutureOrderMaxDays"`
+}
+
+// Location returns the store's time zone. It is time.Local if the store
+// did not give a time zone.
+func (f FutureOrders) Location() *time.Location {
+	if f.TimeZoneMinutes == 0 && f.TimeZoneCode == "" {
+		return time.Local
+	}
+	return time.FixedZone(f.TimeZoneCode, f.TimeZoneMinutes*60)
+}
+
+// Window returns the earliest and latest that an order can be scheduled
+// after the current time. Either one is zero if the store has no limit.
+func (f FutureOrders) Window() (earliest, latest time.Duration) {
+	return time.Duration(f.DelayInHours) * time.Hour, time.Duration(f.MaxDays) * 24 * time.Hour
+}
+
+// IsOpenAt returns true if the hours include the time t. The hours are given
+// in the store's local time so t should be in the same time zone as the store.
+func (h *StoreHours) IsOpenAt(t time.Time) bool {
+	for _, r := range h.periods(t) {
+		if !t.Before(r.open) && t.Before(r.close) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen returns the next time after t that the store will open. The
+// boolean is false if the hours are empty.
+func (h *StoreHours) NextOpen(t time.Time) (time.Time, bool) {
+	var (
+		next  time.Time
+		found bool
+	)
+	for _, r := range h.periods(t) {
+		if r.open.After(t) && (!found || r.open.Before(next)) {
+			next, found = r.open, true
+		}
+	}
+	return next, found
+}
+
+type period struct {
+	open, close time.Time
+}
+
+// periods returns the times the store is open from the day before t to a
+// week after so that hours that close after midnight are included.
+func (h *StoreHours) periods(t time.Time) []period {
+	var (
+		periods []period
+		start   = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	)
+	for i := -1; i <= 7; i++ {
+		day := start.AddDate(0, 0, i)
+		for _, hours := range h.day(day.Weekday()) {
+			open, err1 := clockTime(day, hours.OpenTime)
+			close, err2 := clockTime(day, hours.CloseTime)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			if !close.After(open) {
+				close = close.AddDate(0, 0, 1)
+			}
+			periods = append(periods, period{open: open, close: close})
+		}
+	}
+	return periods
+}
+
+func (h *StoreHours) day(d time.Weekday) []struct {
+	OpenTime  string
+	CloseTime string
+} {
+	switch d {
+	case time.Sunday:
+		return h.Sun
+	case time.Monday:
+		return h.Mon
+	case time.Tuesday:
+		return h.Tue
+	case time.Wednesday:
+		return h.Wed
+	case time.Thursday:
+		return h.Thu
+	case time.Friday:
+		return h.Fri
+	case time.Saturday:
+		return h.Sat
+	}
+	return nil
+}
+
+// clockTime returns the time on a day given a clock time like "10:30".
+func clockTime(day time.Time, clock string) (time.Time, error) {
+	c, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), c.Hour(), c.Minute(), 0, 0, day.Location()), nil
+}
@@ -142,6 +142,34 @@ func (p *Product) GetVariants(container ItemContainer) (variants []*Variant) {
 	return variants
 }
 
+// ToppingCodes returns the codes of all the toppings that can be added to the
+// product (see AvailableToppings).
+func (p *Product) ToppingCodes() []string {
+	if p.AvailableToppings == "" {
+		return nil
+	}
+	var codes []string
+	for _, kv := range strings.Split(p.AvailableToppings, ",") {
+		if code := strings.Split(kv, "=")[0]; code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// DefaultToppingAmounts are the amounts of a topping that dominos accepts
+// for products that do not list their own.
+var DefaultToppingAmounts = []string{"0", "0.5", "1", "1.5", "2"}
+
+// ToppingAmounts returns the amounts that a topping can be added to the
+// product with, ex. "0.5" for light or "1.5" for extra.
+func (p *Product) ToppingAmounts() []string {
+	if qtys := p.optionQtys(); len(qtys) > 0 {
+		return qtys
+	}
+	return DefaultToppingAmounts
+}
+
 func (p *Product) optionQtys() (optqtys []string) {
 	if qtys, ok := p.Tags["OptionQtys"]; ok {
 		oq := qtys.([]interface{})
@@ -0,0 +1,129 @@
+package dawg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// RequestLogger is used to log the http requests sent to dominos and their
+// responses. The bodies given to a RequestLogger are not redacted, see Redact.
+type RequestLogger interface {
+	LogRequest(req *http.Request, body []byte)
+	LogResponse(resp *http.Response, body []byte)
+}
+
+type loggerKey struct{}
+
+// WithLogger returns a context that will log all requests sent to dominos
+// with the given RequestLogger.
+func WithLogger(ctx context.Context, l RequestLogger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+func loggerFrom(ctx context.Context) RequestLogger {
+	l, _ := ctx.Value(loggerKey{}).(RequestLogger)
+	return l
+}
+
+// readBody reads the body of a request and replaces it so that
+// it can be sent.
+func readBody(req *http.Request) []byte {
+	if req.Body == nil {
+		return nil
+	}
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// redacted is the set of json keys that hold payment information. Keys are
+// compared in lower case.
+var redacted = map[string]bool{
+	"number":       true,
+	"expiration":   true,
+	"securitycode": true,
+	"cardid":       true,
+	"otp":          true,
+	"password":     true,
+}
+
+// Redact returns a json body with all of the payment fields replaced. If the
+// body is not json, only the length of the body is returned.
+func Redact(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return []byte(fmt.Sprintf("[%d bytes]", len(body)))
+	}
+	b, err := json.Marshal(redact(v))
+	if err != nil {
+		return []byte(fmt.Sprintf("[%d bytes]", len(body)))
+	}
+	return b
+}
+
+func redact(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, inner := range val {
+			if !redacted[strings.ToLower(k)] {
+				val[k] = redact(inner)
+				continue
+			}
+			switch field := inner.(type) {
+			case string:
+				if field != "" {
+					val[k] = "[REDACTED]"
+				}
+			case float64:
+				val[k] = "[REDACTED]"
+			}
+		}
+	case []interface{}:
+		for i := range val {
+			val[i] = redact(val[i])
+		}
+	}
+	return v
+}
+
+type rawKey struct{}
+
+type rawWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// WithRawResponses returns a context that writes the unparsed body of every
+// successful response from dominos to w, each followed by a newline.
+// Responses with a bad status code are not written.
+func WithRawResponses(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, rawKey{}, &rawWriter{w: w})
+}
+
+func writeRaw(ctx context.Context, body []byte) error {
+	raw, ok := ctx.Value(rawKey{}).(*rawWriter)
+	if !ok {
+		return nil
+	}
+	raw.mu.Lock()
+	defer raw.mu.Unlock()
+	if _, err := raw.w.Write(body); err != nil {
+		return err
+	}
+	_, err := raw.w.Write([]byte{'\n'})
+	return err
+}
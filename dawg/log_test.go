@@ -0,0 +1,108 @@
+package dawg
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	body := []byte(`{"Order":{"Payments":[{"Type":"CreditCard","Number":"4100123422343234","Expiration":"0125","SecurityCode":"123","Amount":9.99}],"Email":"jdoe@example.com"}}`)
+	r := string(Redact(body))
+	for _, secret := range []string{"4100123422343234", "0125", "123\""} {
+		if strings.Contains(r, secret) {
+			t.Errorf("redacted body should not contain %s: %s", secret, r)
+		}
+	}
+	for _, s := range []string{"CreditCard", "9.99", "jdoe@example.com", "[REDACTED]"} {
+		if !strings.Contains(r, s) {
+			t.Errorf("redacted body should contain %s: %s", s, r)
+		}
+	}
+	if r = string(Redact([]byte("not json"))); r != "[8 bytes]" {
+		t.Errorf("wrong redacted body for non-json input: %s", r)
+	}
+	if len(Redact(nil)) != 0 {
+		t.Error("empty body should stay empty")
+	}
+}
+
+type testLogger struct {
+	requests, responses int
+	reqBody, respBody   []byte
+}
+
+func (l *testLogger) LogRequest(req *http.Request, body []byte) {
+	l.requests++
+	l.reqBody = body
+}
+
+func (l *testLogger) LogResponse(resp *http.Response, body []byte) {
+	l.responses++
+	l.respBody = body
+}
+
+func TestClientLogger(t *testing.T) {
+	var sent bytes.Buffer
+	cli, done := testServerClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent.ReadFrom(r.Body)
+		w.Write([]byte(`{"Status":0}`))
+	}))
+	defer done()
+
+	l := &testLogger{}
+	ctx := WithLogger(context.Background(), l)
+	if _, err := cli.post(ctx, "/power/price-order", nil, strings.NewReader(`{"a":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	if l.requests != 1 || l.responses != 1 {
+		t.Errorf("expected one request and response to be logged, got %d and %d", l.requests, l.responses)
+	}
+	if string(l.reqBody) != `{"a":1}` || sent.String() != `{"a":1}` {
+		t.Errorf("logging should not change the request body: got %q, sent %q", l.reqBody, sent.String())
+	}
+	if string(l.respBody) != `{"Status":0}` {
+		t.Errorf("wrong response body logged: %q", l.respBody)
+	}
+	if _, err := cli.get(context.Background(), "/", nil); err != nil {
+		t.Fatal(err)
+	}
+	if l.requests != 1 {
+		t.Error("requests should only be logged with a logger in the context")
+	}
+}
+
+func TestRawResponses(t *testing.T) {
+	status := http.StatusOK
+	cli, done := testServerClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"Status":0,"StoreID":"4336"}`))
+	}))
+	defer done()
+
+	buf := &bytes.Buffer{}
+	ctx := WithRawResponses(context.Background(), buf)
+	for i := 0; i < 2; i++ {
+		if _, err := cli.get(ctx, "/power/store/4336/profile", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exp := "{\"Status\":0,\"StoreID\":\"4336\"}\n{\"Status\":0,\"StoreID\":\"4336\"}\n"
+	if buf.String() != exp {
+		t.Errorf("wrong raw responses: got %q, want %q", buf.String(), exp)
+	}
+
+	buf.Reset()
+	status = http.StatusInternalServerError
+	if _, err := cli.get(ctx, "/", nil); err == nil {
+		t.Error("expected an error for a bad status code")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("responses with a bad status should not be written, got %q", buf.String())
+	}
+	if _, err := cli.get(context.Background(), "/", nil); err == nil || buf.Len() != 0 {
+		t.Error("nothing should be written without a raw context")
+	}
+}
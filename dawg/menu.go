@@ -1,6 +1,7 @@
 package dawg
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -102,6 +103,45 @@ func (m *Menu) FindItem(code string) (itm Item) {
 	return nil
 }
 
+// Each calls fn for every item in the food and pre-configured categories of the
+// menu in the order that they are categorized. Items that are in more than one
+// category are only given to fn once. Each stops and returns the first error
+// returned by fn.
+func (m *Menu) Each(fn func(Item) error) error {
+	seen := make(map[string]bool)
+	for _, cat := range []MenuCategory{
+		m.Categorization.Food,
+		m.Categorization.Preconfigured,
+	} {
+		if err := m.eachInCategory(cat, seen, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Menu) eachInCategory(mc MenuCategory, seen map[string]bool, fn func(Item) error) error {
+	for _, code := range mc.Products {
+		if seen[code] {
+			continue
+		}
+		item := m.FindItem(code)
+		if item == nil {
+			continue
+		}
+		seen[code] = true
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	for _, sub := range mc.Categories {
+		if err := m.eachInCategory(sub, seen, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Print will write the menu to an io.Writer.
 func (m *Menu) Print(w io.Writer) {
 	writeMenuCategory(w, m.Categorization.Food, 0)
@@ -238,9 +278,9 @@ func (m *Menu) initVariant(v *Variant) *Variant {
 	return v
 }
 
-func newMenu(c *client, id string) (*Menu, error) {
+func newMenu(ctx context.Context, c *client, id string) (*Menu, error) {
 	path := format("/power/store/%s/menu", id)
-	b, err := c.get(path, Params{"lang": DefaultLang, "structured": "true"})
+	b, err := c.get(ctx, path, Params{"lang": DefaultLang, "structured": "true"})
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,7 @@ package dawg
 import (
 	"bytes"
 	"encoding/gob"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -210,3 +211,63 @@ func TestMenuStorage(t *testing.T) {
 	}
 	tests.Check(os.RemoveAll(testdir))
 }
+
+func TestMenuEach(t *testing.T) {
+	m := &Menu{
+		Products: map[string]*Product{
+			"S_PIZZA": {ItemCommon: ItemCommon{Code: "S_PIZZA"}},
+			"S_WINGS": {ItemCommon: ItemCommon{Code: "S_WINGS"}},
+		},
+		Variants: map[string]*Variant{
+			"14SCREEN": {ItemCommon: ItemCommon{Code: "14SCREEN"}, ProductCode: "S_PIZZA"},
+		},
+		Preconfigured: map[string]*PreConfiguredProduct{
+			"14SCEXTRAV": {ItemCommon: ItemCommon{Code: "14SCEXTRAV"}},
+		},
+	}
+	m.Categorization.Food = MenuCategory{Categories: []MenuCategory{
+		{Products: []string{"S_PIZZA", "14SCREEN"}},
+		{Products: []string{"S_WINGS", "S_PIZZA", "NOTHERE"}}, // repeated and missing codes
+	}}
+	m.Categorization.Preconfigured = MenuCategory{Products: []string{"14SCEXTRAV"}}
+	m.Categorization.Coupons = MenuCategory{Products: []string{"9193"}}
+
+	visited := map[string]int{}
+	var order []string
+	err := m.Each(func(item Item) error {
+		visited[item.ItemCode()]++
+		order = append(order, item.ItemCode())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"S_PIZZA", "14SCREEN", "S_WINGS", "14SCEXTRAV"}
+	if len(order) != len(exp) {
+		t.Fatalf("visited %v, want %v", order, exp)
+	}
+	for i, code := range exp {
+		if order[i] != code {
+			t.Errorf("item %d: got %s, want %s", i, order[i], code)
+		}
+		if visited[code] != 1 {
+			t.Errorf("%s was visited %d times", code, visited[code])
+		}
+	}
+
+	stop := errors.New("stop")
+	n := 0
+	err = m.Each(func(item Item) error {
+		n++
+		if n == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("Each should return the callback's error, got %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Each should stop at the first error, called %d times", n)
+	}
+}
@@ -2,9 +2,13 @@ package dawg
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // TODO: alphabetize the Order struct fields and add some more documentation
@@ -31,12 +35,25 @@ type Order struct {
 	Email         string                 `json:"Email"`
 	Phone         string
 	Payments      []*orderPayment `json:"Payments"`
+	Coupons       []*OrderCoupon  `json:"Coupons,omitempty"`
+
+	// FutureOrderTime is the time that a scheduled order should be ready,
+	// it is empty for orders that are wanted now (see ScheduleFor).
+	FutureOrderTime string `json:",omitempty"`
 
 	// OrderName is not a field that is sent to dominos, but is just a way for
 	// users to name a specific order.
 	OrderName string `json:"-"`
-	price     float64
-	cli       *client
+
+	// Tip is added to the amount of each payment when the order is placed.
+	// It is not part of the order's price.
+	Tip   float64 `json:"-"`
+	price float64
+	cli   *client
+
+	// scheduled is the time given to ScheduleFor, it keeps
+	// the time zone that FutureOrderTime does not have.
+	scheduled time.Time
 }
 
 // InitOrder will make sure that an order is initialized correctly. An order
@@ -57,16 +74,127 @@ func (o *Order) init() {
 
 // PlaceOrder is the method that sends the final order to dominos
 func (o *Order) PlaceOrder() error {
-	if err := o.prepare(); err != nil {
-		return err
+	_, err := Place(context.Background(), o)
+	return err
+}
+
+// Place will price an order and send it to dominos. The order should be fully
+// built, with products, an address, customer information, and payments.
+//
+// If dominos rejects the order, the error returned will be a *DominosError.
+// Warnings sent back by dominos are not returned as errors.
+func Place(ctx context.Context, o *Order) (*Confirmation, error) {
+	if err := o.prepare(ctx); err != nil {
+		return nil, err
+	}
+	b, err := o.cli.post(ctx, "/power/place-order", nil, o.raw())
+	if err != nil {
+		return nil, err
+	}
+	if err = dominosErr(b); err != nil && !IsWarning(err) {
+		return nil, err
+	}
+	resp := struct {
+		Order struct {
+			Confirmation
+			// the estimate is usually a range like "16-26" but
+			// it could also be a number or missing
+			EstimatedWaitMinutes interface{}
+		}
+	}{}
+	if err = json.Unmarshal(b, &resp); err != nil {
+		return nil, err
+	}
+	conf := &resp.Order.Confirmation
+	if conf.OrderID == "" {
+		conf.OrderID = o.OrderID
+	}
+	if conf.Amounts["Customer"] == 0 {
+		conf.Amounts = map[string]float64{"Customer": o.price}
+	}
+	conf.Tip = o.Tip
+	conf.Scheduled, _ = o.ScheduledTime()
+	switch wait := resp.Order.EstimatedWaitMinutes.(type) {
+	case string:
+		conf.EstimatedWait = strings.TrimSpace(wait)
+	case float64:
+		conf.EstimatedWait = strconv.FormatFloat(wait, 'f', -1, 64)
+	}
+	return conf, nil
+}
+
+// Confirmation holds the details that dominos sends back once an order has
+// been placed.
+type Confirmation struct {
+	OrderID      string
+	StoreID      string
+	StoreOrderID string
+	Amounts      map[string]float64
+
+	// Tip is the tip that was paid with the order.
+	Tip float64 `json:"-"`
+
+	// EstimatedWait is the number of minutes until the order is ready or
+	// delivered, usually a range like "16-26". It is empty if dominos did
+	// not give an estimate.
+	EstimatedWait string
+
+	// Scheduled is the time a scheduled order was placed for, it is the
+	// zero time for orders that were not scheduled.
+	Scheduled time.Time `json:"-"`
+}
+
+// Total returns the total price that the customer was charged, including
+// the tip.
+func (c *Confirmation) Total() float64 {
+	return c.Amounts["Customer"] + c.Tip
+}
+
+// FutureOrderTimeFormat is the layout dominos uses for the time of a
+// scheduled order. The time is in the store's local time.
+const FutureOrderTimeFormat = "2006-01-02 15:04:05"
+
+// ScheduleFor makes the order a scheduled order that will be ready at time t
+// instead of as soon as possible. A zero time makes it a regular order again.
+// The time is converted to the store's time zone, loc, because dominos reads
+// it as the store's local time (see Store.Location).
+func (o *Order) ScheduleFor(t time.Time, loc *time.Location) {
+	if t.IsZero() {
+		o.FutureOrderTime = ""
+		o.scheduled = time.Time{}
+		return
 	}
-	return sendOrder("/power/place-order", *o)
+	o.scheduled = t.In(loc)
+	o.FutureOrderTime = o.scheduled.Format(FutureOrderTimeFormat)
+}
+
+// ScheduledTime returns the time that a scheduled order is for and false if
+// the order is not scheduled. Orders that were not scheduled with ScheduleFor,
+// like ones that were decoded from json, are assumed to be in local time.
+func (o *Order) ScheduledTime() (time.Time, bool) {
+	if o.FutureOrderTime == "" {
+		return time.Time{}, false
+	}
+	if !o.scheduled.IsZero() && o.scheduled.Format(FutureOrderTimeFormat) == o.FutureOrderTime {
+		return o.scheduled, true
+	}
+	t, err := time.ParseInLocation(FutureOrderTimeFormat, o.FutureOrderTime, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
 }
 
 // Price method returns the total price of an order.
 func (o *Order) Price() (float64, error) {
+	return o.PriceContext(context.Background())
+}
+
+// PriceContext returns the total price of an order using a context for
+// the request.
+func (o *Order) PriceContext(ctx context.Context) (float64, error) {
 	if o.price == 0.0 {
-		if err := o.prepare(); err != nil {
+		if err := o.prepare(ctx); err != nil {
 			return -1.0, err
 		}
 	}
@@ -114,6 +242,38 @@ func (o *Order) RemoveProduct(code string) error {
 	return nil
 }
 
+// AddCoupon will add a coupon to the order given a coupon code. Adding a
+// coupon that is already in the order does nothing.
+func (o *Order) AddCoupon(code string) {
+	id := 0
+	for _, c := range o.Coupons {
+		if c.Code == code {
+			return
+		}
+		if c.ID > id {
+			id = c.ID
+		}
+	}
+	// ids are never reused so that a removed coupon does
+	// not leave two coupons with the same id
+	o.Coupons = append(o.Coupons, &OrderCoupon{
+		Code: code,
+		Qty:  1,
+		ID:   id + 1,
+	})
+}
+
+// RemoveCoupon will remove the coupon with the given code from the order.
+func (o *Order) RemoveCoupon(code string) error {
+	for i, c := range o.Coupons {
+		if c.Code == code {
+			o.Coupons = append(o.Coupons[:i], o.Coupons[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("coupon not in order")
+}
+
 // AddPayment adds a payment object to an order
 //
 // Deprecated. use AddCard
@@ -127,6 +287,12 @@ func (o *Order) AddCard(c Card) {
 	o.Payments = append(o.Payments, makeOrderPaymentFromCard(c))
 }
 
+// AddSavedCard will add a card that has been saved by dominos as a method of
+// payment.
+func (o *Order) AddSavedCard(c *SavedCard) {
+	o.Payments = append(o.Payments, makeOrderPaymentFromSavedCard(c))
+}
+
 // Name returns the name that was set by the user.
 func (o *Order) Name() string {
 	return o.OrderName
@@ -144,12 +310,12 @@ func (o *Order) Validate() error {
 }
 
 // only returns dominos failures or non-dominos errors.
-func (o *Order) prepare() error {
+func (o *Order) prepare(ctx context.Context) error {
 	if o.cli == nil {
 		o.cli = orderClient
 	}
 
-	odata, err := getPricingData(*o)
+	odata, err := getPricingData(ctx, *o)
 	if err != nil && !IsWarning(err) {
 		return err
 	}
@@ -161,7 +327,8 @@ func (o *Order) prepare() error {
 
 		n := len(o.Payments)
 		for i := 0; i < n; i++ {
-			o.Payments[i].Amount = p
+			o.Payments[i].Amount = p + o.Tip
+			o.Payments[i].TipAmount = o.Tip
 		}
 	}
 	return nil
@@ -170,13 +337,18 @@ func (o *Order) prepare() error {
 // ValidateOrder sends and order to the validation endpoint to be validated by
 // Dominos' servers.
 func ValidateOrder(order *Order) error {
+	return ValidateOrderContext(context.Background(), order)
+}
+
+// ValidateOrderContext sends an order to the validation endpoint using a
+// context for the request.
+func ValidateOrderContext(ctx context.Context, order *Order) error {
 	if order.cli == nil {
 		order.cli = orderClient
 	}
-	err := sendOrder("/power/validate-order", *order)
-	if IsWarning(err) {
+	err := sendOrder(ctx, "/power/validate-order", *order)
+	if e, ok := isDominosErr(err); ok && e.Status == WarningStatus {
 		// TODO: make it possible to recognize the warning as an 'AutoAddedOrderId' warning.
-		e := err.(*DominosError)
 		order.OrderID = e.Order.OrderID
 	}
 	return err
@@ -209,16 +381,16 @@ func (o *Order) raw() *bytes.Buffer {
 	return buf
 }
 
-func sendOrder(path string, order Order) error {
-	b, err := order.cli.post(path, nil, order.raw())
+func sendOrder(ctx context.Context, path string, order Order) error {
+	b, err := order.cli.post(ctx, path, nil, order.raw())
 	if err != nil {
 		return err
 	}
 	return dominosErr(b)
 }
 
-func orderRequest(path string, order *Order) (map[string]interface{}, error) {
-	b, err := order.cli.post(path, nil, order.raw())
+func orderRequest(ctx context.Context, path string, order *Order) (map[string]interface{}, error) {
+	b, err := order.cli.post(ctx, path, nil, order.raw())
 	respData := map[string]interface{}{}
 
 	if err := errpair(err, json.Unmarshal(b, &respData)); err != nil {
@@ -231,12 +403,12 @@ func orderRequest(path string, order *Order) (map[string]interface{}, error) {
 func getOrderPrice(order Order) (map[string]interface{}, error) {
 	// fmt.Println("deprecated... use getPricingData")
 	order.Payments = []*orderPayment{}
-	return orderRequest("/power/price-order", &order)
+	return orderRequest(context.Background(), "/power/price-order", &order)
 }
 
-func getPricingData(order Order) (*priceingData, error) {
+func getPricingData(ctx context.Context, order Order) (*priceingData, error) {
 	order.Payments = []*orderPayment{}
-	b, err := order.cli.post("/power/price-order", nil, order.raw())
+	b, err := order.cli.post(ctx, "/power/price-order", nil, order.raw())
 	resp := &priceingData{}
 	if err := errpair(err, json.Unmarshal(b, resp)); err != nil {
 		return nil, err
@@ -255,6 +427,14 @@ type pricedOrder struct {
 	PulseOrderGUID   string `json:"PulseOrderGuid"`
 }
 
+// OrderCoupon is a coupon that is sent to dominos within the Order struct.
+type OrderCoupon struct {
+	// Code is the coupon code found on the dominos menu.
+	Code string `json:"Code"`
+	Qty  int    `json:"Qty"`
+	ID   int    `json:"ID"`
+}
+
 // OrderProduct represents an item that will be sent to and from dominos within
 // the Order struct.
 type OrderProduct struct {
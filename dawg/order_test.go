@@ -1,9 +1,11 @@
 package dawg
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -14,7 +16,7 @@ import (
 func TestGetOrderPrice(t *testing.T) {
 	defer swapclient(1)()
 	o := Order{cli: orderClient}
-	_, err := getPricingData(o)
+	_, err := getPricingData(context.Background(), o)
 	if err == nil {
 		t.Error("should have returned an error")
 	}
@@ -66,7 +68,7 @@ func TestGetOrderPrice(t *testing.T) {
 		t.Error("Should have raised an error", err)
 	}
 
-	err = order.prepare()
+	err = order.prepare(context.Background())
 	if !IsFailure(err) {
 		t.Error("Should have returned a dominos failure", err)
 	}
@@ -125,7 +127,7 @@ func TestPrepareOrder(t *testing.T) {
 
 	menu := testingMenu()
 	tests.Check(o.AddProduct(menu.FindItem("10SCREEN")))
-	tests.Check(o.prepare())
+	tests.Check(o.prepare(context.Background()))
 	if o.price <= 0.0 {
 		t.Error("cached price should not be zero or less")
 	}
@@ -301,6 +303,17 @@ func TestCard(t *testing.T) {
 	tests.StrEq(op.Number, c.Num(), "bad number")
 	tests.StrEq(op.Expiration, formatDate(c.ExpiresOn()), "bad expiration")
 	tests.StrEq(op.SecurityCode, c.Code(), "bad cvv")
+
+	o := &Order{}
+	o.AddSavedCard(&SavedCard{ID: "abc123", CardType: "Visa", CVV: "123"})
+	if len(o.Payments) != 1 {
+		t.Fatal("failed to add a saved card")
+	}
+	op = o.Payments[0]
+	tests.StrEq(op.CardID, "abc123", "bad card id")
+	tests.StrEq(op.Number, "", "a saved card should not have a card number")
+	tests.StrEq(op.SecurityCode, "123", "bad cvv")
+	tests.StrEq(op.Type, "CreditCard", "bad payment type")
 }
 
 func TestOrderToJSON(t *testing.T) {
@@ -319,6 +332,28 @@ func TestOrderToJSON(t *testing.T) {
 		t.Error("OrderToJSON should return an indented order")
 	}
 
+	if strings.Contains(plain, "FutureOrderTime") {
+		t.Error("orders that are not scheduled should not have a FutureOrderTime")
+	}
+	// the time is sent in the store's time zone
+	eastern := FutureOrders{TimeZoneMinutes: -240, TimeZoneCode: "GMT-04:00"}.Location()
+	later := time.Date(2020, time.May, 1, 22, 30, 0, 0, time.UTC)
+	o.ScheduleFor(later, eastern)
+	if s = o.raw().String(); !strings.Contains(s, `"FutureOrderTime":"2020-05-01 18:30:00"`) {
+		t.Errorf("scheduled order has the wrong FutureOrderTime: %s", s)
+	}
+	if when, ok := o.ScheduledTime(); !ok || !when.Equal(later) || when.Location() != eastern {
+		t.Errorf("wrong scheduled time: %v", when)
+	}
+	decoded := &Order{FutureOrderTime: o.FutureOrderTime}
+	if when, ok := decoded.ScheduledTime(); !ok || when.Hour() != 18 || when.Location() != time.Local {
+		t.Errorf("orders without a time zone should use local time, got %v", when)
+	}
+	o.ScheduleFor(time.Time{}, eastern)
+	if _, ok := o.ScheduledTime(); ok {
+		t.Error("a zero time should unschedule the order")
+	}
+
 	s = o.raw().String()
 	if !strings.HasPrefix(s, "{\"Order\":") {
 		t.Error("Order.raw should be prefixed by '{\"Order\":'")
@@ -331,14 +366,14 @@ func TestOrderToJSON(t *testing.T) {
 func TestOrderCalls(t *testing.T) {
 	o := new(Order)
 	o.Init()
-	err := sendOrder("/power/validate-order", *o)
+	err := sendOrder(context.Background(), "/power/validate-order", *o)
 	if !IsFailure(err) || err == nil {
 		t.Error("expected error")
 	}
 
 	o = new(Order)
 	InitOrder(o)
-	err = sendOrder("", *o)
+	err = sendOrder(context.Background(), "", *o)
 	if err == nil {
 		t.Error("expected error")
 	}
@@ -369,3 +404,109 @@ func TestCardTypeRegex(t *testing.T) {
 		}
 	}
 }
+
+func TestCoupons(t *testing.T) {
+	o := &Order{}
+	o.AddCoupon("9193")
+	o.AddCoupon("9193")
+	o.AddCoupon("5152")
+	if len(o.Coupons) != 2 {
+		t.Fatal("should not add the same coupon twice")
+	}
+	if o.Coupons[1].Code != "5152" || o.Coupons[1].ID != 2 || o.Coupons[1].Qty != 1 {
+		t.Error("bad coupon")
+	}
+	if err := o.RemoveCoupon("9193"); err != nil {
+		t.Error(err)
+	}
+	if len(o.Coupons) != 1 || o.Coupons[0].Code != "5152" {
+		t.Error("wrong coupon removed")
+	}
+	if err := o.RemoveCoupon("9193"); err == nil {
+		t.Error("expected error")
+	}
+
+	o = &Order{}
+	o.AddCoupon("A")
+	o.AddCoupon("B")
+	if err := o.RemoveCoupon("A"); err != nil {
+		t.Error(err)
+	}
+	o.AddCoupon("C")
+	if len(o.Coupons) != 2 {
+		t.Fatal("wrong number of coupons")
+	}
+	if o.Coupons[0].ID != 2 || o.Coupons[1].ID != 3 {
+		t.Errorf("coupon ids should not be reused after a coupon is removed, got %d and %d", o.Coupons[0].ID, o.Coupons[1].ID)
+	}
+}
+
+func TestPlace(t *testing.T) {
+	tests.InitHelpers(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/power/price-order", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Status":0,"Order":{"OrderID":"abc123","Amounts":{"Customer":12.5}}}`))
+	})
+	fail := false
+	wait := "16-26"
+	mux.HandleFunc("/power/place-order", func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.Write([]byte(`{"Status":-1,"Order":{"Status":-1,"StatusItems":[{"Code":"PosOrderIncomplete"}]}}`))
+			return
+		}
+		w.Write([]byte(`{"Status":0,"Order":{"OrderID":"abc123","StoreID":"4336","StoreOrderID":"2020-05-01#1","Amounts":{"Customer":12.5},"EstimatedWaitMinutes":"` + wait + `"}}`))
+	})
+	cli, done := testServerClient(mux)
+	defer done()
+
+	o := &Order{StoreID: "4336", cli: cli}
+	o.AddCard(NewCard("38790546741937", "01/25", 123))
+	conf, err := Place(context.Background(), o)
+	tests.Check(err)
+	tests.StrEq(conf.OrderID, "abc123", "wrong order id")
+	tests.StrEq(conf.StoreOrderID, "2020-05-01#1", "wrong store order id")
+	if conf.Total() != 12.5 {
+		t.Error("wrong total")
+	}
+	if o.Payments[0].Amount != 12.5 {
+		t.Error("the payment amount should be set before placing the order")
+	}
+	tests.StrEq(conf.EstimatedWait, "16-26", "wrong wait estimate")
+
+	o.Tip = 3
+	conf, err = Place(context.Background(), o)
+	tests.Check(err)
+	if o.Payments[0].Amount != 15.5 || o.Payments[0].TipAmount != 3 {
+		t.Error("the tip should be added to the payment amount")
+	}
+	if conf.Total() != 15.5 || conf.Tip != 3 {
+		t.Error("the total should include the tip")
+	}
+	o.Tip = 0
+
+	wait = ""
+	conf, err = Place(context.Background(), o)
+	tests.Check(err)
+	tests.StrEq(conf.EstimatedWait, "", "estimate should be empty when dominos does not send one")
+	if !conf.Scheduled.IsZero() {
+		t.Error("the order was not scheduled")
+	}
+
+	later := time.Date(2020, time.May, 1, 18, 30, 0, 0, time.Local)
+	o.ScheduleFor(later, time.Local)
+	conf, err = Place(context.Background(), o)
+	tests.Check(err)
+	if !conf.Scheduled.Equal(later) {
+		t.Errorf("wrong scheduled time: %v", conf.Scheduled)
+	}
+	o.ScheduleFor(time.Time{}, time.Local)
+
+	fail = true
+	conf, err = Place(context.Background(), o)
+	if !IsFailure(err) {
+		t.Error("expected a dominos failure")
+	}
+	if conf != nil {
+		t.Error("should not get a confirmation for a failed order")
+	}
+}
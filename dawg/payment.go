@@ -82,6 +82,29 @@ func (p *Payment) Code() string {
 
 var _ Card = (*Payment)(nil)
 
+// SavedCard is a card that has already been saved by dominos. It is sent
+// using the id that dominos gave the card (see UserCard) so the card number
+// is never needed.
+type SavedCard struct {
+	// ID is the card id given by dominos.
+	ID string
+
+	// CardType is the type of card, i.e. "Visa" or "MasterCard".
+	CardType   string
+	PostalCode string
+	CVV        string
+}
+
+func makeOrderPaymentFromSavedCard(c *SavedCard) *orderPayment {
+	return &orderPayment{
+		CardID:       c.ID,
+		SecurityCode: c.CVV,
+		Type:         "CreditCard",
+		CardType:     c.CardType,
+		PostalCode:   c.PostalCode,
+	}
+}
+
 func makeOrderPaymentFromCard(c Card) *orderPayment {
 	return &orderPayment{
 		Number:       c.Num(),
@@ -153,7 +176,8 @@ type orderPayment struct {
 	// These next fields are just for dominos
 
 	Amount         float64
-	CardID         string `json:"CardID,omitempty"`
+	TipAmount      float64 `json:",omitempty"`
+	CardID         string  `json:"CardID,omitempty"`
 	ProviderID     string
 	OTP            string
 	GpmPaymentType string `json:"gpmPaymentType,omitempty"`
@@ -0,0 +1,81 @@
+package dawg
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type rateLimitKey struct{}
+
+// RateLimiter is a token bucket that limits how often requests are sent
+// to dominos. A nil RateLimiter does not limit anything.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a RateLimiter that allows perSecond requests
+// every second. A rate that is not positive is unlimited.
+func NewRateLimiter(perSecond float64) *RateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &RateLimiter{rate: perSecond, burst: 1, tokens: 1}
+}
+
+// Wait blocks until a request can be sent. It will return the context's
+// error if the context is done before then.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	for {
+		wait := l.reserve(time.Now())
+		if wait <= 0 {
+			return nil
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			// don't wait for a request that could never be sent in time
+			return context.DeadlineExceeded
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// reserve takes a token if there is one, otherwise it returns the time
+// until the next token is added.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// WithRateLimit returns a context that will make every request sent to
+// dominos wait for the rate limiter instead of being sent right away.
+func WithRateLimit(ctx context.Context, l *RateLimiter) context.Context {
+	return context.WithValue(ctx, rateLimitKey{}, l)
+}
+
+func rateLimitFrom(ctx context.Context) *RateLimiter {
+	l, _ := ctx.Value(rateLimitKey{}).(*RateLimiter)
+	return l
+}
@@ -0,0 +1,73 @@
+package dawg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+type retryKey struct{}
+
+type retryPolicy struct {
+	retries int
+	wait    time.Duration
+}
+
+// WithRetry returns a context that will make GET requests (store lookups,
+// menus, etc.) be retried up to the given number of times when dominos
+// responds with a 5xx status code or there is a network error. The wait
+// time between attempts is doubled after every retry.
+//
+// Requests that send or change an order are never retried so that an order
+// cannot be sent twice.
+func WithRetry(ctx context.Context, retries int, wait time.Duration) context.Context {
+	return context.WithValue(ctx, retryKey{}, retryPolicy{retries: retries, wait: wait})
+}
+
+func retryFrom(ctx context.Context) retryPolicy {
+	p, _ := ctx.Value(retryKey{}).(retryPolicy)
+	return p
+}
+
+// statusError is returned when the server responds with a status other than
+// 200 OK.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("dawg.client.do: bad status code %d", e.code)
+}
+
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code >= 500
+	}
+	var ne net.Error
+	return errors.As(err, &ne)
+}
+
+// withRetries calls fn until it succeeds or the context's retry policy
+// is used up.
+func withRetries(ctx context.Context, fn func() ([]byte, error)) ([]byte, error) {
+	policy := retryFrom(ctx)
+	wait := policy.wait
+	for i := 0; ; i++ {
+		b, err := fn()
+		if err == nil || i >= policy.retries || !retryable(err) {
+			return b, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
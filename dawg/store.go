@@ -1,10 +1,13 @@
 package dawg
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -27,18 +30,33 @@ const (
 )
 
 // NearestStore gets the dominos location closest to the given address.
+// Stores are checked in order of distance and the first one that is online
+// is returned.
 //
 // The addr argument should be the address to deliver to not the address of the
 // store itself. The service should be either "Carryout" or "Delivery", this will
 // determine wether the final order will be for pickup or delivery.
 func NearestStore(addr Address, service string) (*Store, error) {
-	return getNearestStore(orderClient, addr, service)
+	return NearestStoreContext(context.Background(), addr, service)
+}
+
+// NearestStoreContext is the same as NearestStore except it uses a context
+// for all of its requests.
+func NearestStoreContext(ctx context.Context, addr Address, service string) (*Store, error) {
+	return getNearestStore(ctx, orderClient, addr, service)
 }
 
 // GetNearbyStores is a way of getting all the nearby stores
-// except they will by full initialized.
+// except they will by full initialized. The stores are sorted
+// by distance from the address (see SortByDistance).
 func GetNearbyStores(addr Address, service string) ([]*Store, error) {
-	return asyncNearbyStores(orderClient, addr, service)
+	return GetNearbyStoresContext(context.Background(), addr, service)
+}
+
+// GetNearbyStoresContext is the same as GetNearbyStores except it uses a
+// context for all of its requests.
+func GetNearbyStoresContext(ctx context.Context, addr Address, service string) ([]*Store, error) {
+	return asyncNearbyStores(ctx, orderClient, addr, service)
 }
 
 // NewStore returns the default Store object given a store id.
@@ -50,8 +68,14 @@ func GetNearbyStores(addr Address, service string) ([]*Store, error) {
 // The addr argument should be the address to deliver to not the address of the
 // store itself.
 func NewStore(id string, service string, addr Address) (*Store, error) {
+	return NewStoreContext(context.Background(), id, service, addr)
+}
+
+// NewStoreContext is the same as NewStore except it uses a context for
+// the request.
+func NewStoreContext(ctx context.Context, id string, service string, addr Address) (*Store, error) {
 	store := &Store{userService: service, userAddress: addr, cli: orderClient}
-	return store, InitStore(id, store)
+	return store, initStore(ctx, orderClient, id, store)
 }
 
 // InitStore allows for the creation of arbitrary store objects. The main
@@ -64,7 +88,7 @@ func NewStore(id string, service string, addr Address) (*Store, error) {
 //	err := dawg.InitStore(id, &store)
 // This will allow all of the fields sent in the api to be viewed.
 func InitStore(id string, obj interface{}) error {
-	return initStore(orderClient, id, obj)
+	return initStore(context.Background(), orderClient, id, obj)
 }
 
 var orderClient = &client{
@@ -79,9 +103,9 @@ var orderClient = &client{
 	},
 }
 
-func initStore(cli *client, id string, obj interface{}) error {
+func initStore(ctx context.Context, cli *client, id string, obj interface{}) error {
 	path := fmt.Sprintf(profileEndpoint, id)
-	b, err := cli.get(path, nil)
+	b, err := cli.get(ctx, path, nil)
 	if err != nil {
 		return err
 	}
@@ -124,6 +148,10 @@ type Store struct {
 
 	MinDeliveryOrderAmnt float64 `json:"MinimumDeliveryOrderAmount"`
 
+	// FutureOrders has the store's time zone and its limits for
+	// scheduled orders.
+	FutureOrders
+
 	Status int
 
 	userAddress Address
@@ -142,11 +170,17 @@ type StoreHours struct {
 
 // Menu returns the menu for a store object
 func (s *Store) Menu() (*Menu, error) {
+	return s.MenuContext(context.Background())
+}
+
+// MenuContext returns the menu for a store object using a context for
+// the request.
+func (s *Store) MenuContext(ctx context.Context) (*Menu, error) {
 	var err error
 	if s.menu != nil && s.menu.ID == s.ID {
 		return s.menu, nil
 	}
-	s.menu, err = newMenu(s.cli, s.ID)
+	s.menu, err = newMenu(ctx, s.cli, s.ID)
 	return s.menu, err
 }
 
@@ -219,11 +253,11 @@ type storeLocs struct {
 	Stores      []*Store    `json:"Stores"`
 }
 
-func getNearestStore(c *client, addr Address, service string) (*Store, error) {
+func getNearestStore(ctx context.Context, c *client, addr Address, service string) (*Store, error) {
 	if addr == nil {
 		return nil, errors.New("no address")
 	}
-	locs, err := findNearbyStores(c, addr, service)
+	locs, err := findNearbyStores(ctx, c, addr, service)
 	if err != nil {
 		return nil, err
 	}
@@ -236,17 +270,17 @@ func getNearestStore(c *client, addr Address, service string) (*Store, error) {
 		}
 	}
 	store.userAddress, store.userService = addr, service
-	return store, initStore(c, store.ID, store)
+	return store, initStore(ctx, c, store.ID, store)
 }
 
-func findNearbyStores(c *client, addr Address, service string) (*storeLocs, error) {
+func findNearbyStores(ctx context.Context, c *client, addr Address, service string) (*storeLocs, error) {
 	if !(service == Delivery || service == Carryout) {
 		// panic("service must be either 'Delivery' or 'Carryout'")
 		return nil, ErrBadService
 	}
 	// TODO: on the dominos website, the c param can sometimes be just the zip code
 	// and it still works.
-	b, err := c.get("/power/store-locator", &Params{
+	b, err := c.get(ctx, "/power/store-locator", &Params{
 		"s":    addr.LineOne(),
 		"c":    format("%s, %s %s", addr.City(), addr.StateCode(), addr.Zip()),
 		"type": service,
@@ -259,13 +293,17 @@ func findNearbyStores(c *client, addr Address, service string) (*storeLocs, erro
 	if err != nil {
 		return nil, err
 	}
+	for _, s := range locs.Stores {
+		s.userAddress, s.userService = addr, service
+	}
+	SortByDistance(locs.Stores)
 	return locs, dominosErr(b)
 }
 
-func asyncNearbyStores(cli *client, addr Address, service string) ([]*Store, error) {
-	all, err := findNearbyStores(cli, addr, service)
+func asyncNearbyStores(ctx context.Context, cli *client, addr Address, service string) ([]*Store, error) {
+	all, err := findNearbyStores(ctx, cli, addr, service)
 	if err != nil {
-		return nil, fmt.Errorf("findNearbyStores: %v", err)
+		return nil, fmt.Errorf("findNearbyStores: %w", err)
 	}
 
 	var (
@@ -285,7 +323,7 @@ func asyncNearbyStores(cli *client, addr Address, service string) ([]*Store, err
 	go func() {
 		defer close(builder.stores)
 		for i, store = range all.Stores {
-			go builder.initStore(cli, store.ID, i)
+			go builder.initStore(ctx, cli, store.ID, i)
 		}
 
 		builder.Wait()
@@ -299,6 +337,12 @@ func asyncNearbyStores(cli *client, addr Address, service string) ([]*Store, err
 		store.userAddress = addr
 		store.userService = service
 		store.cli = cli
+		// the store profile does not include the distance from the address
+		store.MinDistance = all.Stores[pair.index].MinDistance
+		store.MaxDistance = all.Stores[pair.index].MaxDistance
+		if _, ok := store.Coordinates(); !ok {
+			store.StoreCoords = all.Stores[pair.index].StoreCoords
+		}
 
 		stores[pair.index] = store
 	}
@@ -306,6 +350,106 @@ func asyncNearbyStores(cli *client, addr Address, service string) ([]*Store, err
 	return stores, err
 }
 
+// StoreErrors holds the errors for the stores that could not be found by
+// GetNearestStoresContext. The keys are the store ids.
+type StoreErrors map[string]error
+
+func (e StoreErrors) Error() string {
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	msgs := make([]string, len(ids))
+	for i, id := range ids {
+		msgs[i] = fmt.Sprintf("store %s: %v", id, e[id])
+	}
+	return "could not get all of the stores: " + strings.Join(msgs, "; ")
+}
+
+// GetNearestStoresContext gets the n stores nearest to the address, or all of
+// the nearby stores if n is zero, using at most the number of workers given
+// to get the details of each store concurrently. The stores are sorted by
+// distance (see SortByDistance).
+//
+// Stores that could not be found are left out of the list and their errors
+// are returned as StoreErrors so that one store failing does not lose the
+// rest of them. When the context is done, the stores that have not been found
+// yet are given the context's error.
+func GetNearestStoresContext(ctx context.Context, addr Address, service string, n, workers int) ([]*Store, error) {
+	return nearestStores(ctx, orderClient, addr, service, n, workers)
+}
+
+func nearestStores(ctx context.Context, c *client, addr Address, service string, n, workers int) ([]*Store, error) {
+	all, err := findNearbyStores(ctx, c, addr, service)
+	if err != nil {
+		return nil, err
+	}
+	found := all.Stores
+	if n > 0 && n < len(found) {
+		found = found[:n]
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		stores = make([]*Store, len(found))
+		errs   = make([]error, len(found))
+		jobs   = make(chan int)
+		wg     sync.WaitGroup
+	)
+	for w := 0; w < workers && w < len(found); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				store := &Store{}
+				if err := initStore(ctx, c, found[i].ID, store); err != nil {
+					errs[i] = err
+					continue
+				}
+				store.userAddress, store.userService = addr, service
+				// the store profile does not include the distance from the address
+				store.MinDistance = found[i].MinDistance
+				store.MaxDistance = found[i].MaxDistance
+				if _, ok := store.Coordinates(); !ok {
+					store.StoreCoords = found[i].StoreCoords
+				}
+				stores[i] = store
+			}
+		}()
+	}
+send:
+	for i := range found {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			for ; i < len(found); i++ {
+				errs[i] = ctx.Err()
+			}
+			break send
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	result := make([]*Store, 0, len(found))
+	storeErrs := StoreErrors{}
+	for i := range found {
+		if errs[i] != nil {
+			storeErrs[found[i].ID] = errs[i]
+		} else {
+			result = append(result, stores[i])
+		}
+	}
+	SortByDistance(result)
+	if len(storeErrs) > 0 {
+		return result, storeErrs
+	}
+	return result, nil
+}
+
 type storebuilder struct {
 	sync.WaitGroup
 	stores chan maybeStore
@@ -317,12 +461,12 @@ type maybeStore struct {
 	err   error
 }
 
-func (sb *storebuilder) initStore(cli *client, id string, index int) {
+func (sb *storebuilder) initStore(ctx context.Context, cli *client, id string, index int) {
 	defer sb.Done()
 	path := fmt.Sprintf(profileEndpoint, id)
 	store := &Store{}
 
-	b, err := cli.get(path, nil)
+	b, err := cli.get(ctx, path, nil)
 	if err != nil {
 		sb.stores <- maybeStore{store: nil, err: err, index: -1}
 	}
@@ -1,8 +1,14 @@
 package dawg
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/harrybrwn/apizza/pkg/tests"
 )
@@ -57,7 +63,7 @@ func TestNearestStore_Err(t *testing.T) {
 func TestGetAllNearbyStores(t *testing.T) {
 	tests.InitHelpers(t)
 	addr := testAddress()
-	validation, err := findNearbyStores(orderClient, addr, "Delivery")
+	validation, err := findNearbyStores(context.Background(), orderClient, addr, "Delivery")
 	if err != nil {
 		t.Error(err)
 	}
@@ -129,7 +135,7 @@ func TestInitStore_Err(t *testing.T) {
 	ids := []string{"", "0000", "999999999999", "-7765"}
 	for _, id := range ids {
 		s := new(Store)
-		err := initStore(orderClient, id, s)
+		err := initStore(context.Background(), orderClient, id, s)
 		if err == nil {
 			t.Error("expected error from a ridiculous store id")
 		}
@@ -139,7 +145,7 @@ func TestInitStore_Err(t *testing.T) {
 func TestGetNearestStore(t *testing.T) {
 	a := testAddress()
 	for _, service := range []string{Delivery, Carryout} {
-		s, err := getNearestStore(orderClient, a, service)
+		s, err := getNearestStore(context.Background(), orderClient, a, service)
 		if err != nil {
 			t.Error(err)
 		}
@@ -148,3 +154,137 @@ func TestGetNearestStore(t *testing.T) {
 		}
 	}
 }
+
+func TestNearestStores(t *testing.T) {
+	tests.InitHelpers(t)
+	var (
+		mu              sync.Mutex
+		running, maxRun int
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/power/store-locator", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Status":0,"Stores":[
+			{"StoreID":"4","MinDistance":4},
+			{"StoreID":"1","MinDistance":1},
+			{"StoreID":"bad","MinDistance":2},
+			{"StoreID":"3","MinDistance":3},
+			{"StoreID":"5","MinDistance":5}]}`))
+	})
+	mux.HandleFunc("/power/store/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		running++
+		if running > maxRun {
+			maxRun = running
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+		time.Sleep(5 * time.Millisecond)
+		id := strings.Split(r.URL.Path, "/")[3]
+		if id == "bad" {
+			w.Write([]byte(`{"Status":-1,"StatusItems":[{"Code":"StoreNotFound"}]}`))
+			return
+		}
+		fmt.Fprintf(w, `{"Status":0,"StoreID":%q,"StoreCoordinates":{"StoreLatitude":"1","StoreLongitude":"1"}}`, id)
+	})
+	cli, done := testServerClient(mux)
+	defer done()
+	ctx := context.Background()
+
+	stores, err := nearestStores(ctx, cli, testAddress(), Carryout, 4, 2)
+	serr, ok := err.(StoreErrors)
+	if !ok {
+		t.Fatalf("expected StoreErrors, got %v", err)
+	}
+	if _, ok = serr["bad"]; !ok || len(serr) != 1 {
+		t.Error("wrong store errors:", serr)
+	}
+	ids := make([]string, len(stores))
+	for i, s := range stores {
+		ids[i] = s.ID
+	}
+	tests.StrEq(strings.Join(ids, ","), "1,3,4", "should get the nearest stores sorted by distance")
+	if stores[0].Distance() != 1 {
+		t.Error("store should keep the distance from the store locator")
+	}
+	if maxRun > 2 {
+		t.Errorf("should have at most 2 concurrent requests, got %d", maxRun)
+	}
+
+	stores, err = nearestStores(ctx, cli, testAddress(), Carryout, 2, 10)
+	tests.Check(err)
+	if len(stores) != 2 {
+		t.Error("should only get the top 2 stores")
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err = nearestStores(canceled, cli, testAddress(), Carryout, 0, 1); err == nil {
+		t.Error("expected an error from a canceled context")
+	}
+}
+
+func TestStoreHours(t *testing.T) {
+	var h StoreHours
+	err := json.Unmarshal([]byte(`{
+		"Mon":[{"OpenTime":"10:30","CloseTime":"01:00"}],
+		"Tue":[{"OpenTime":"10:30","CloseTime":"14:00"},{"OpenTime":"17:00","CloseTime":"22:00"}]
+	}`), &h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2020-04-13 is a monday
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2020, time.April, day, hour, min, 0, 0, time.UTC)
+	}
+	for _, tc := range []struct {
+		t    time.Time
+		open bool
+		next time.Time
+	}{
+		{at(13, 9, 0), false, at(13, 10, 30)},
+		{at(13, 10, 30), true, at(14, 10, 30)},
+		{at(14, 0, 30), true, at(14, 10, 30)}, // still open from monday night
+		{at(14, 1, 0), false, at(14, 10, 30)},
+		{at(14, 15, 0), false, at(14, 17, 0)},
+		{at(14, 22, 0), false, at(20, 10, 30)},
+	} {
+		if open := h.IsOpenAt(tc.t); open != tc.open {
+			t.Errorf("IsOpenAt(%v) gave %v, want %v", tc.t, open, tc.open)
+		}
+		next, ok := h.NextOpen(tc.t)
+		if !ok || !next.Equal(tc.next) {
+			t.Errorf("NextOpen(%v) gave %v, want %v", tc.t, next, tc.next)
+		}
+	}
+	if _, ok := (&StoreHours{}).NextOpen(at(13, 9, 0)); ok {
+		t.Error("empty hours should never open")
+	}
+}
+
+func TestFutureOrders(t *testing.T) {
+	var s Store
+	err := json.Unmarshal([]byte(`{
+		"TimeZoneMinutes": -300, "TimeZoneCode": "GMT-05:00",
+		"FutureOrderDelayInHours": 1, "FutureOrderMaxDays": 21
+	}`), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, off := time.Date(2020, time.May, 1, 0, 0, 0, 0, s.Location()).Zone(); off != -5*60*60 {
+		t.Errorf("wrong time zone offset: %d", off)
+	}
+	earliest, latest := s.Window()
+	if earliest != time.Hour || latest != 21*24*time.Hour {
+		t.Errorf("wrong scheduling window: %v to %v", earliest, latest)
+	}
+	if (FutureOrders{}).Location() != time.Local {
+		t.Error("stores without a time zone should use local time")
+	}
+	if earliest, latest = (FutureOrders{}).Window(); earliest != 0 || latest != 0 {
+		t.Error("stores without limits should have an empty window")
+	}
+}
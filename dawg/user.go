@@ -1,6 +1,7 @@
 package dawg
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -87,7 +88,7 @@ func (u *UserProfile) StoresNearMe() ([]*Store, error) {
 	if err := u.addressCheck(); err != nil {
 		return nil, err
 	}
-	return asyncNearbyStores(u.auth.cli, u.DefaultAddress(), u.ServiceMethod)
+	return asyncNearbyStores(context.Background(), u.auth.cli, u.DefaultAddress(), u.ServiceMethod)
 }
 
 // NearestStore will find the the store that is closest to the user's default address.
@@ -104,7 +105,7 @@ func (u *UserProfile) NearestStore(service string) (*Store, error) {
 	if err = u.addressCheck(); err != nil {
 		return nil, err
 	}
-	u.store, err = getNearestStore(c, u.DefaultAddress(), service)
+	u.store, err = getNearestStore(context.Background(), c, u.DefaultAddress(), service)
 	return u.store, err
 }
 
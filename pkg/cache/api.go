@@ -2,8 +2,6 @@ package cache
 
 import (
 	"time"
-
-	"github.com/boltdb/bolt"
 )
 
 // Getter is an object that gets.
@@ -33,14 +31,29 @@ type TimeStamper interface {
 	ResetTimeStamp(string) error
 }
 
+// ExpiryStorage defines objects that can store values that will expire.
+type ExpiryStorage interface {
+	PutWithTTL(string, []byte, time.Duration) error
+	GetWithExpiry(string) ([]byte, bool, error)
+}
+
 type internalDB interface {
 	internal
 	DB
 }
 
 type internal interface {
-	view(func(*bolt.Bucket) error) error
-	update(func(*bolt.Bucket) error) error
+	view(func(bucket) error) error
+	update(func(bucket) error) error
+}
+
+// bucket is the part of a bolt.Bucket that is used by the DataBase so that
+// it can also be implemented in memory.
+type bucket interface {
+	Get(key []byte) []byte
+	Put(key, value []byte) error
+	Delete(key []byte) error
+	ForEach(fn func(k, v []byte) error) error
 }
 
 // Im not sure if i'll need these but i'll leave them here just in case.
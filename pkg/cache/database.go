@@ -53,6 +53,7 @@ func GetDB(dbfile string) (db *DataBase, err error) {
 
 type innerdb struct {
 	db            *bolt.DB
+	mem           *memStore // used instead of db for in-memory databases
 	defaultBucket []byte
 	bucketHEAD    []byte
 	path          string
@@ -60,14 +61,14 @@ type innerdb struct {
 
 // Put stores bytes the database
 func (idb *innerdb) Put(key string, val []byte) error {
-	return idb.update(func(b *bolt.Bucket) error {
+	return idb.update(func(b bucket) error {
 		return b.Put([]byte(key), val)
 	})
 }
 
 // Get will retrieve the value given a key
 func (idb *innerdb) Get(key string) (raw []byte, err error) {
-	err = idb.view(func(b *bolt.Bucket) error {
+	err = idb.view(func(b bucket) error {
 		raw = b.Get([]byte(key))
 		return nil
 	})
@@ -76,7 +77,7 @@ func (idb *innerdb) Get(key string) (raw []byte, err error) {
 
 // Delete removes the data for a specific key.
 func (idb *innerdb) Delete(key string) error {
-	return idb.update(func(b *bolt.Bucket) error {
+	return idb.update(func(b bucket) error {
 		return b.Delete([]byte(key))
 	})
 }
@@ -87,7 +88,7 @@ func (db *DataBase) Exists(key string) bool {
 }
 
 func exists(db internal, key string) (exists bool) {
-	if err := db.view(func(b *bolt.Bucket) error {
+	if err := db.view(func(b bucket) error {
 		data := b.Get([]byte(key))
 
 		if data == nil {
@@ -108,13 +109,16 @@ func (idb *innerdb) Path() string {
 
 // Close will close the DataBase's inner bolt.DB
 func (idb *innerdb) Close() error {
+	if idb.mem != nil {
+		return nil
+	}
 	return idb.db.Close()
 }
 
 // Destroy will close the database and completely delete the database file.
 func (db *DataBase) Destroy() error {
 	err := db.Close()
-	if err != nil {
+	if err != nil || db.mem != nil {
 		return err
 	}
 	return os.Remove(db.Path())
@@ -123,7 +127,7 @@ func (db *DataBase) Destroy() error {
 // Map returns a map of all the key-value pairs in the database.
 func (db *DataBase) Map() (all map[string][]byte, err error) {
 	all = map[string][]byte{}
-	return all, db.view(func(b *bolt.Bucket) error {
+	return all, db.view(func(b bucket) error {
 		return b.ForEach(func(k, v []byte) error {
 			all[string(k)] = v
 			return nil
@@ -131,6 +135,35 @@ func (db *DataBase) Map() (all map[string][]byte, err error) {
 	})
 }
 
+// Clear deletes every key in the database's bucket and returns the number of
+// values that were deleted, not counting their timestamps.
+func (db *DataBase) Clear() (n int, err error) {
+	err = db.update(func(b bucket) error {
+		var keys [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			keys = append(keys, append([]byte{}, k...))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err = b.Delete(k); err != nil {
+				return err
+			}
+			if !isMetaKey(string(k)) {
+				n++
+			}
+		}
+		return nil
+	})
+	return n, err
+}
+
+func isMetaKey(key string) bool {
+	return strings.HasSuffix(key, timestampSuffix) || strings.HasSuffix(key, expirySuffix)
+}
+
 // WithBucket temporarily sets the bucket to the string given and returns the
 // database with the new bucket.
 //
@@ -138,6 +171,10 @@ func (db *DataBase) Map() (all map[string][]byte, err error) {
 // Map, TimeStamp, and UpdateTS (any method that calls view or update internally).
 func (db *DataBase) WithBucket(bucket string) *DataBase {
 	db.bucketHEAD = []byte(bucket)
+	if db.mem != nil {
+		db.mem.createBucket(db.bucketHEAD)
+		return db
+	}
 	db.db.Update(func(tx *bolt.Tx) error {
 		_, err := tx.CreateBucketIfNotExists([]byte(db.bucketHEAD))
 		return err
@@ -158,12 +195,19 @@ func (db *DataBase) DeleteBucket(name string) error {
 	if name == string(db.defaultBucket) {
 		panic("cannot delete default bucket")
 	}
+	if db.mem != nil {
+		return db.mem.deleteBucket([]byte(name))
+	}
 	return db.db.Update(func(tx *bolt.Tx) error {
 		return tx.DeleteBucket([]byte(name))
 	})
 }
 
-func (idb *innerdb) view(fn func(*bolt.Bucket) error) error {
+func (idb *innerdb) view(fn func(bucket) error) error {
+	if idb.mem != nil {
+		defer idb.resetHEAD()
+		return idb.mem.view(idb.bucketHEAD, fn)
+	}
 	return idb.db.View(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(idb.bucketHEAD))
 		defer idb.resetHEAD()
@@ -171,7 +215,11 @@ func (idb *innerdb) view(fn func(*bolt.Bucket) error) error {
 	})
 }
 
-func (idb *innerdb) update(fn func(*bolt.Bucket) error) error {
+func (idb *innerdb) update(fn func(bucket) error) error {
+	if idb.mem != nil {
+		defer idb.resetHEAD()
+		return idb.mem.update(idb.bucketHEAD, fn)
+	}
 	return idb.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(idb.bucketHEAD))
 		defer idb.resetHEAD()
@@ -190,10 +238,12 @@ func filename(file string) string {
 	return strings.TrimSuffix(name, filepath.Ext(name))
 }
 
+// ensurePath will create any of the parent directories of path
+// that do not exist.
 func ensurePath(path string) (err error) {
 	p := filepath.Dir(path)
 	if _, err := os.Stat(p); os.IsNotExist(err) {
-		return os.Mkdir(p, 0777)
+		return os.MkdirAll(p, 0700)
 	}
 	return err
 }
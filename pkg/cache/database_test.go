@@ -24,6 +24,16 @@ func TestUtils(t *testing.T) {
 	if err := os.Remove(filepath.Join(os.TempDir(), "test_dir")); err != nil {
 		t.Error(err)
 	}
+	nested := filepath.Join(os.TempDir(), "test_dir", "nested", "name")
+	if err := ensurePath(nested); err != nil {
+		t.Error(err)
+	}
+	if stat, err := os.Stat(filepath.Dir(nested)); err != nil || !stat.IsDir() {
+		t.Error("ensurePath should create all of the parent directories")
+	}
+	if err := os.RemoveAll(filepath.Join(os.TempDir(), "test_dir")); err != nil {
+		t.Error(err)
+	}
 }
 
 func TestGetDB(t *testing.T) {
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const expirySuffix = "_expires"
+
+// PutWithTTL stores a value in the database along with a timestamp and a
+// time-to-live. Once the ttl has passed, the value will be seen as expired by
+// GetWithExpiry. A ttl of zero or less will store the value without an
+// expiration.
+func (db *DataBase) PutWithTTL(key string, val []byte, ttl time.Duration) error {
+	now := time.Now()
+	return db.update(func(b bucket) error {
+		if err := b.Put([]byte(key), val); err != nil {
+			return err
+		}
+		stamp := strconv.FormatInt(now.Unix(), 10)
+		if err := b.Put([]byte(ts(key)), []byte(stamp)); err != nil {
+			return err
+		}
+		if ttl <= 0 {
+			return b.Delete([]byte(exp(key)))
+		}
+		expires := strconv.FormatInt(now.Add(ttl).UnixNano(), 10)
+		return b.Put([]byte(exp(key)), []byte(expires))
+	})
+}
+
+// GetWithExpiry will get a value from the database and tell whether or not
+// the value has expired. Expired values are deleted from the database as they
+// are read, along with their timestamps, and will be returned as nil.
+//
+// Values stored without a ttl will never expire.
+func (db *DataBase) GetWithExpiry(key string) (raw []byte, expired bool, err error) {
+	err = db.update(func(b bucket) error {
+		raw = b.Get([]byte(key))
+		rawExp := b.Get([]byte(exp(key)))
+		if rawExp == nil {
+			return nil
+		}
+		expires, err := strconv.ParseInt(string(rawExp), 10, 64)
+		if err != nil {
+			return err
+		}
+		if time.Now().UnixNano() < expires {
+			return nil
+		}
+		raw, expired = nil, true
+		for _, k := range []string{key, ts(key), exp(key)} {
+			if err = b.Delete([]byte(k)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return raw, expired, nil
+}
+
+// Prune deletes all of the expired values in the database along with their
+// timestamps and returns the number of values that were deleted.
+func (db *DataBase) Prune() (n int, err error) {
+	now := time.Now().UnixNano()
+	err = db.update(func(b bucket) error {
+		var expired []string
+		err := b.ForEach(func(k, v []byte) error {
+			key := string(k)
+			if !strings.HasSuffix(key, expirySuffix) {
+				return nil
+			}
+			expires, err := strconv.ParseInt(string(v), 10, 64)
+			if err == nil && now >= expires {
+				expired = append(expired, strings.TrimSuffix(key, expirySuffix))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// keys cannot be deleted while iterating through a bolt bucket
+		for _, key := range expired {
+			for _, k := range []string{key, ts(key), exp(key)} {
+				if err = b.Delete([]byte(k)); err != nil {
+					return err
+				}
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+func exp(key string) string {
+	return key + expirySuffix
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestExpiry(t *testing.T) {
+	tests.InitHelpers(t)
+	db, err := GetDB(tests.TempFile())
+	tests.Fatal(err)
+	defer func() { tests.Check(db.Destroy()) }()
+
+	tests.Check(db.PutWithTTL("key", []byte("value"), time.Hour))
+	raw, expired, err := db.GetWithExpiry("key")
+	tests.Check(err)
+	if expired {
+		t.Error("value should not have expired yet")
+	}
+	tests.StrEq(string(raw), "value", "wrong value")
+	stamp, err := db.TimeStamp("key")
+	tests.Check(err)
+	if time.Since(stamp) > time.Minute {
+		t.Error("timestamp should have been stored with the value")
+	}
+
+	tests.Check(db.PutWithTTL("short", []byte("value"), time.Millisecond))
+	time.Sleep(time.Millisecond * 5)
+	raw, expired, err = db.GetWithExpiry("short")
+	tests.Check(err)
+	if !expired {
+		t.Error("value should have expired")
+	}
+	if raw != nil {
+		t.Error("expired value should be nil")
+	}
+	for _, k := range []string{"short", ts("short"), exp("short")} {
+		if db.Exists(k) {
+			t.Errorf("%s should have been deleted", k)
+		}
+	}
+
+	tests.Check(db.Put("forever", []byte("value")))
+	raw, expired, err = db.GetWithExpiry("forever")
+	tests.Check(err)
+	if expired || string(raw) != "value" {
+		t.Error("values without a ttl should not expire")
+	}
+	tests.Check(db.PutWithTTL("forever", []byte("value"), 0))
+	if db.Exists(exp("forever")) {
+		t.Error("a zero ttl should not store an expiration")
+	}
+}
+
+func TestPruneClear(t *testing.T) {
+	tests.InitHelpers(t)
+	disk, err := GetDB(tests.TempFile())
+	tests.Fatal(err)
+	defer func() { tests.Check(disk.Destroy()) }()
+
+	for _, db := range []*DataBase{disk, NewMemoryDB()} {
+		tests.Check(db.PutWithTTL("old", []byte("value"), time.Millisecond))
+		tests.Check(db.PutWithTTL("older", []byte("value"), time.Millisecond))
+		tests.Check(db.PutWithTTL("new", []byte("value"), time.Hour))
+		tests.Check(db.Put("forever", []byte("value")))
+		tests.Check(db.WithBucket("other").Put("key", []byte("value")))
+		time.Sleep(time.Millisecond * 5)
+
+		n, err := db.Prune()
+		tests.Check(err)
+		if n != 2 {
+			t.Errorf("should have pruned 2 values, got %d", n)
+		}
+		for _, k := range []string{"old", ts("old"), exp("old"), "older"} {
+			if db.Exists(k) {
+				t.Errorf("%s should have been pruned", k)
+			}
+		}
+		if !db.Exists("new") || !db.Exists("forever") {
+			t.Error("prune should only delete expired values")
+		}
+
+		n, err = db.Clear()
+		tests.Check(err)
+		if n != 2 {
+			t.Errorf("should have cleared 2 values, got %d", n)
+		}
+		all, err := db.Map()
+		tests.Check(err)
+		if len(all) != 0 {
+			t.Errorf("clear should delete everything, %d keys are left", len(all))
+		}
+		if !db.WithBucket("other").Exists("key") {
+			t.Error("clear should only delete the keys in the current bucket")
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/boltdb/bolt"
+)
+
+// NewMemoryDB returns a DataBase that is kept entirely in memory. It has the
+// same behavior as a DataBase from GetDB but nothing is ever written to disk
+// which makes it useful for tests.
+func NewMemoryDB() *DataBase {
+	name := []byte("memory")
+	mem := &memStore{buckets: make(map[string]memBucket)}
+	mem.createBucket(name)
+	return &DataBase{
+		innerdb: &innerdb{
+			defaultBucket: name,
+			mem:           mem,
+			bucketHEAD:    name,
+		},
+	}
+}
+
+type memStore struct {
+	mu      sync.RWMutex
+	buckets map[string]memBucket
+}
+
+func (s *memStore) view(name []byte, fn func(bucket) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.buckets[string(name)]
+	if !ok {
+		b = memBucket{}
+	}
+	return fn(b)
+}
+
+func (s *memStore) update(name []byte, fn func(bucket) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.buckets[string(name)]
+	if !ok {
+		b = memBucket{}
+		s.buckets[string(name)] = b
+	}
+	return fn(b)
+}
+
+func (s *memStore) createBucket(name []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.buckets[string(name)]; !ok {
+		s.buckets[string(name)] = memBucket{}
+	}
+}
+
+func (s *memStore) deleteBucket(name []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.buckets[string(name)]; !ok {
+		return bolt.ErrBucketNotFound
+	}
+	delete(s.buckets, string(name))
+	return nil
+}
+
+// memBucket stores copies of all the values it is given so that callers
+// cannot change the stored data.
+type memBucket map[string][]byte
+
+func (b memBucket) Get(key []byte) []byte {
+	val, ok := b[string(key)]
+	if !ok {
+		return nil
+	}
+	return append([]byte{}, val...)
+}
+
+func (b memBucket) Put(key, value []byte) error {
+	if len(key) == 0 {
+		return bolt.ErrKeyRequired
+	}
+	b[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+func (b memBucket) Delete(key []byte) error {
+	delete(b, string(key))
+	return nil
+}
+
+// ForEach calls fn for every key-value pair in the bucket
+// sorted by key, the same as a bolt.Bucket.
+func (b memBucket) ForEach(fn func(k, v []byte) error) error {
+	keys := make([]string, 0, len(b))
+	for k := range b {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := fn([]byte(k), b.Get([]byte(k))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestMemoryDB(t *testing.T) {
+	db := NewMemoryDB()
+	val := []byte("this is a test")
+	if err := db.Put("key", val); err != nil {
+		t.Fatal(err)
+	}
+	val[0] = 'T'
+	raw, err := db.Get("key")
+	if err != nil {
+		t.Error(err)
+	}
+	if string(raw) != "this is a test" {
+		t.Errorf("stored value should not change with the original slice, got %q", raw)
+	}
+	if raw, _ = db.Get("nothere"); raw != nil {
+		t.Error("missing keys should give nil")
+	}
+	if !db.Exists("key") || db.Exists("nothere") {
+		t.Error("db.Exists does not match reality")
+	}
+	if err = db.Put("", val); err == nil {
+		t.Error("expected an error for an empty key")
+	}
+
+	if err = db.WithBucket("other").Put("key", []byte("other value")); err != nil {
+		t.Error(err)
+	}
+	raw, _ = db.Get("key")
+	if string(raw) != "this is a test" {
+		t.Error("bucket should reset after WithBucket")
+	}
+	all, err := db.WithBucket("other").Map()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(all) != 1 || !bytes.Equal(all["key"], []byte("other value")) {
+		t.Errorf("wrong map from bucket: %v", all)
+	}
+	if err = db.DeleteBucket("other"); err != nil {
+		t.Error(err)
+	}
+	if db.WithBucket("other").Exists("key") {
+		t.Error("bucket should have been deleted")
+	}
+	if err = db.DeleteBucket("nobucket"); err == nil {
+		t.Error("expected an error for a missing bucket")
+	}
+
+	if err = db.Delete("key"); err != nil {
+		t.Error(err)
+	}
+	if db.Exists("key") {
+		t.Error("key should have been deleted")
+	}
+
+	if _, err = db.TimeStamp("menu"); err != nil {
+		t.Error(err)
+	}
+	if !db.Exists(ts("menu")) {
+		t.Error("TimeStamp should store a new timestamp")
+	}
+	if err = db.PutWithTTL("exp", []byte("x"), -time.Second); err != nil {
+		t.Error(err)
+	}
+	if raw, expired, err := db.GetWithExpiry("exp"); err != nil || expired || string(raw) != "x" {
+		t.Error("value without a ttl should not expire")
+	}
+
+	if db.Path() != "" {
+		t.Error("in-memory database should not have a path")
+	}
+	if err = db.Destroy(); err != nil {
+		t.Error(err)
+	}
+}
@@ -46,6 +46,29 @@ func SetConfig(foldername string, c Config) error {
 	return cfg.init()
 }
 
+// SetConfigFile is the same as SetConfig except that the config is read from
+// and saved to file instead of the config.json file in the folder. The folder
+// is still used for everything else. The file has to exist already, a missing
+// file is an error so that a mistyped path does not start an empty config.
+func SetConfigFile(foldername, file string, c Config) error {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	cfg = configfile{
+		conf: c,
+		dir:  getdir(foldername),
+		file: file,
+	}
+	if _, err = os.Stat(file); os.IsNotExist(err) {
+		return fmt.Errorf("config file %s does not exist", file)
+	}
+	if err = cfg.init(); err != nil {
+		return fmt.Errorf("could not read config file %s: %v", file, err)
+	}
+	return nil
+}
+
 // SetNonFileConfig sets a configuration struct without creating a file.
 func SetNonFileConfig(c Config) error {
 	cfg = configfile{
@@ -153,6 +153,67 @@ func TestSetConfig(t *testing.T) {
 	}
 }
 
+func TestSetConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apizza-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "joe.json")
+
+	c := &testCnfg{}
+	err = SetConfigFile(".testconfig", file, c)
+	if err == nil || err.Error() != "config file "+file+" does not exist" {
+		t.Errorf("expected an error for a missing config file, got %v", err)
+	}
+	if _, err = os.Stat(file); !os.IsNotExist(err) {
+		t.Error("a missing config file should not be created")
+	}
+
+	if err = ioutil.WriteFile(file, []byte(`{"number": 50}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = SetConfigFile(".testconfig", file, c); err != nil {
+		t.Fatal(err)
+	}
+	if File() != file {
+		t.Errorf("wrong config file: %s", File())
+	}
+	if c.Number != 50 {
+		t.Error("the config file should have been read")
+	}
+	c.Number2 = 7
+	if err = Save(); err != nil {
+		t.Error(err)
+	}
+	c = &testCnfg{}
+	if err = SetConfigFile(".testconfig", file, c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Number2 != 7 {
+		t.Error("changes should be saved to the same file")
+	}
+
+	if err = ioutil.WriteFile(file, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	err = SetConfigFile(".testconfig", file, &testCnfg{})
+	if err == nil || !strings.Contains(err.Error(), "could not read config file "+file) {
+		t.Errorf("expected an error for a bad config file, got %v", err)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	keys := Keys(&testCnfg{})
+	exp := []string{"test", "msg", "number", "number2", "more.one", "more.two", "f", "pi"}
+	if !reflect.DeepEqual(keys, exp) {
+		t.Errorf("got %v, want %v", keys, exp)
+	}
+	if GetField(&testCnfg{}, "test.nested") != nil {
+		t.Error("should not find a field inside a string")
+	}
+}
+
 func TestEmptyConfig(t *testing.T) {
 	elem := reflect.ValueOf(&testCnfg{}).Elem()
 	expected := `{
@@ -104,6 +104,30 @@ func FieldName(c Config, key string) string {
 	return name
 }
 
+// Keys returns the config keys of all the fields that can be set with
+// SetField. The keys of nested fields are joined with a '.'.
+func Keys(c Config) []string {
+	return keys(reflect.ValueOf(c).Elem().Type(), "")
+}
+
+func keys(typ reflect.Type, prefix string) []string {
+	var all []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, ok := field.Tag.Lookup("config")
+		if !ok {
+			name = field.Name
+		}
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			all = append(all, keys(field.Type, prefix+name+".")...)
+		case reflect.String, reflect.Int, reflect.Float32, reflect.Float64:
+			all = append(all, prefix+name)
+		}
+	}
+	return all
+}
+
 // PrintAll prints out the config struct.
 func PrintAll(config interface{}) error {
 	return FprintAll(os.Stdout, config)
@@ -118,6 +142,9 @@ func FprintAll(w io.Writer, config interface{}) error {
 }
 
 func find(val reflect.Value, keys []string) (string, *reflect.StructField, reflect.Value) {
+	if val.Kind() != reflect.Struct {
+		return "", nil, reflect.ValueOf(nil)
+	}
 	typ := val.Type()
 	for i := 0; i < typ.NumField(); i++ {
 